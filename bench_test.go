@@ -91,3 +91,27 @@ func bench7(b *testing.B, f HandRankFunc, n int) {
 }
 
 var benchR HandRank
+
+func BenchmarkOmaha(b *testing.B) {
+	for _, typ := range []Type{Omaha, OmahaHiLo, OmahaFive, OmahaSix} {
+		n := 4
+		switch typ {
+		case OmahaFive:
+			n = 5
+		case OmahaSix:
+			n = 6
+		}
+		typ, v := typ, make([][]Card, 64)
+		for i := 0; i < len(v); i++ {
+			v[i] = allCards[i%32 : i%32+n+5]
+		}
+		b.Run(typ.Name(), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				hand := v[i%len(v)]
+				benchH = NewHand(typ, hand[:n], hand[n:])
+			}
+		})
+	}
+}
+
+var benchH *Hand
@@ -3,6 +3,12 @@ package cardrank
 func init() {
 	flushes, unique5 = cactusMaps()
 	cactus = Cactus
+	cactusParts = func(bits, suits, product uint32) HandRank {
+		if suits != 0 {
+			return flushes[product]
+		}
+		return unique5[product]
+	}
 }
 
 // flushes is the flush map.
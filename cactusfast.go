@@ -4,6 +4,19 @@ package cardrank
 
 func init() {
 	cactusFast = CactusFast
+	cactusFastParts = func(bits, suits, product uint32) HandRank {
+		if suits != 0 {
+			return fastFlushes[bits]
+		}
+		if r := fastUnique5[bits]; r != 0 {
+			return r
+		}
+		u := 0xe91aaa35 + product
+		u ^= u >> 16
+		u += u << 8
+		u ^= u >> 4
+		return HandRank(hash[(u+(u<<2))>>19^uint32(hashAdjust[(u>>8)&0x1ff])])
+	}
 }
 
 // CactusFast is a fast Cactus Kev hand rank func, implementing Paul Senzee's
@@ -324,6 +324,30 @@ type Card uint32
 // InvalidCard is an invalid card.
 const InvalidCard = Card(^uint32(0))
 
+// Jokers.
+//
+// Jokers have no rank or suit, and are represented in text as an 'X' followed
+// by the joker's color (ex: Xb Xr).
+const (
+	BlackJoker = Card(jokerRank << 8)
+	RedJoker   = Card(jokerRank<<8 | 1)
+)
+
+// jokerRank is the rank value of jokers.
+const jokerRank = 13
+
+// Joker returns the joker for the color rune ('b' or 'r', case-insensitive),
+// or InvalidCard.
+func Joker(color rune) Card {
+	switch color {
+	case 'B', 'b':
+		return BlackJoker
+	case 'R', 'r':
+		return RedJoker
+	}
+	return InvalidCard
+}
+
 // New creates a card for the specified rank and suit.
 func New(rank Rank, suit Suit) Card {
 	if Ace < rank || (suit != Spade && suit != Heart && suit != Diamond && suit != Club) {
@@ -335,6 +359,10 @@ func New(rank Rank, suit Suit) Card {
 // FromRune creates a card from a unicode playing card rune.
 func FromRune(r rune) Card {
 	switch {
+	case r == UnicodeBlackJoker:
+		return BlackJoker
+	case r == UnicodeRedJoker:
+		return RedJoker
 	case unicode.Is(rangeS, r):
 		return New(runeCardRank(r, UnicodeSpadeAce), Spade)
 	case unicode.Is(rangeH, r):
@@ -356,15 +384,24 @@ func FromString(s string) Card {
 	case 1:
 		return FromRune(v[0])
 	case 2:
+		if v[0] == 'X' || v[0] == 'x' {
+			return Joker(v[1])
+		}
 		return New(RankFromRune(v[0]), SuitFromRune(v[1]))
 	}
 	return InvalidCard
 }
 
-// FromIndex creates a card from a numerical index (0-51).
+// FromIndex creates a card from a numerical index (0-51, or 52 and 53 for the
+// black and red jokers).
 func FromIndex(i int) Card {
-	if i < 52 {
+	switch {
+	case i < 52:
 		return New(Rank(i%13), Suit(1<<(i/13)))
+	case i == 52:
+		return BlackJoker
+	case i == 53:
+		return RedJoker
 	}
 	return InvalidCard
 }
@@ -375,7 +412,8 @@ func FromIndex(i int) Card {
 //
 // Cards can described using common text strings (such as "Ah", "ah", "aH", or
 // "AH"), or having a white or black unicode pip for the suit (such as "J♤" or
-// "K♠"), or single unicode playing card runes (such as "🃆" or "🂣").
+// "K♠"), or single unicode playing card runes (such as "🃆" or "🂣"). Jokers
+// can be described as "Xb" or "Xr", or with the joker runes ("🃏" or "🂿").
 func Parse(v ...string) ([]Card, error) {
 	var hand []Card
 	for n, s := range v {
@@ -409,6 +447,9 @@ func Parse(v ...string) ([]Card, error) {
 				c, i = 'T', i+1
 			}
 			card := New(RankFromRune(c), SuitFromRune(r[i+1]))
+			if c == 'X' || c == 'x' {
+				card = Joker(r[i+1])
+			}
 			if card == InvalidCard {
 				return nil, &ParseError{
 					S:   s,
@@ -442,6 +483,9 @@ func (c Card) Rank() Rank {
 
 // RankByte returns the rank byte of the card.
 func (c Card) RankByte() byte {
+	if c.Joker() {
+		return 'X'
+	}
 	return c.Rank().Byte()
 }
 
@@ -455,8 +499,15 @@ func (c Card) Suit() Suit {
 	return Suit(c >> 12 & 0xf)
 }
 
-// SuitByte returns the suit byte of the card.
+// SuitByte returns the suit byte of the card. For jokers, returns the
+// joker's color ('b' or 'r').
 func (c Card) SuitByte() byte {
+	switch c {
+	case BlackJoker:
+		return 'b'
+	case RedJoker:
+		return 'r'
+	}
 	return c.Suit().Byte()
 }
 
@@ -467,9 +518,20 @@ func (c Card) SuitIndex() int {
 
 // Index returns the index of the card.
 func (c Card) Index() int {
+	switch c {
+	case BlackJoker:
+		return 52
+	case RedJoker:
+		return 53
+	}
 	return c.SuitIndex()*13 + c.RankIndex()
 }
 
+// Joker returns true when the card is a joker.
+func (c Card) Joker() bool {
+	return c == BlackJoker || c == RedJoker
+}
+
 // AceIndex returns the Ace low index of the card.
 func (c Card) AceIndex() int {
 	return int(c>>8&0xf+1) % 13
@@ -477,9 +539,10 @@ func (c Card) AceIndex() int {
 
 // UnmarshalText satisfies the encoding.TextUnmarshaler interface.
 func (c *Card) UnmarshalText(buf []byte) error {
-	var err error
-	*c = FromString(string(buf))
-	return err
+	if *c = FromString(string(buf)); *c == InvalidCard {
+		return ErrInvalidCard
+	}
+	return nil
 }
 
 // MarshalText satisfies the encoding.TextMarshaler interface.
@@ -517,7 +580,15 @@ func (c Card) String() string {
 //	l - plural suit name, lower cased (spades hearts diamonds clubs)
 //	L - plural suit name, title cased (Spades Hearts Diamonds Clubs)
 //	d - base 10 integer value
+//
+// Jokers are formatted as 'X' followed by the joker's color (ex: Xb Xr), with
+// the joker runes (🃏 🂿) for c and C, and names (ex: Black Joker) for n, N, p,
+// P, t, T, l, and L.
 func (c Card) Format(f fmt.State, verb rune) {
+	if c.Joker() {
+		c.formatJoker(f, verb)
+		return
+	}
 	r, s := c.Rank(), c.Suit()
 	var buf []byte
 	switch verb {
@@ -572,6 +643,47 @@ func (c Card) Format(f fmt.State, verb rune) {
 	_, _ = f.Write(buf)
 }
 
+// formatJoker formats the joker.
+func (c Card) formatJoker(f fmt.State, verb rune) {
+	name, r := "Black", UnicodeBlackJoker
+	if c == RedJoker {
+		name, r = "Red", UnicodeRedJoker
+	}
+	var buf []byte
+	switch verb {
+	case 's', 'S', 'v', 'b', 'h':
+		buf = append(buf, c.RankByte(), c.SuitByte())
+		if verb == 'S' {
+			buf = bytes.ToUpper(buf)
+		}
+	case 'q':
+		buf = append(buf, '"', c.RankByte(), c.SuitByte(), '"')
+	case 'r':
+		buf = append(buf, c.RankByte())
+	case 'u', 'B', 'H':
+		buf = append(buf, c.SuitByte())
+	case 'c', 'C':
+		buf = append(buf, string(r)...)
+	case 'n', 'N':
+		buf = append(buf, name+" Joker"...)
+	case 'p', 'P':
+		buf = append(buf, name+" Jokers"...)
+	case 't', 'T':
+		buf = append(buf, name...)
+	case 'l', 'L':
+		buf = append(buf, name+"s"...)
+	case 'd':
+		buf = append(buf, strconv.Itoa(int(c))...)
+	default:
+		buf = append(buf, fmt.Sprintf("%%!%c(ERROR=unknown verb, card: %s)", verb, c)...)
+	}
+	switch verb {
+	case 'n', 'p', 't', 'l':
+		buf = bytes.ToLower(buf)
+	}
+	_, _ = f.Write(buf)
+}
+
 // CardFormatter wraps formatting a set of cards. Allows `go test` to function
 // without disabling vet.
 type CardFormatter []Card
@@ -620,6 +732,8 @@ const (
 	UnicodeDiamondWhite rune = '♢'
 	UnicodeClubBlack    rune = '♣'
 	UnicodeClubWhite    rune = '♧'
+	UnicodeBlackJoker   rune = '🃏'
+	UnicodeRedJoker     rune = '🂿'
 )
 
 // runeCardRank converts the unicode rune offset to a card rank.
@@ -651,7 +765,7 @@ func init() {
 	copy(a[14:28], h[:])
 	copy(a[28:42], d[:])
 	copy(a[42:56], c[:])
-	rangeA = newRangeTable(a...)
+	rangeA = newRangeTable(append(a, UnicodeBlackJoker, UnicodeRedJoker)...)
 }
 
 // range tables for unicode playing card runes.
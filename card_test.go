@@ -24,6 +24,8 @@ func TestParse(t *testing.T) {
 		{"As Ks", []Card{New(Ace, Spade), New(King, Spade)}, nil},
 		{" 🂬   a♣  🃚  🂸  td ", []Card{New(Jack, Spade), New(Ace, Club), New(Ten, Club), New(Eight, Heart), New(Ten, Diamond)}, nil},
 		{"10D 10C 10S 10h", []Card{New(Ten, Diamond), New(Ten, Club), New(10, Spade), New(10, Heart)}, nil},
+		{"Xb xR 🃏🂿 Ah", []Card{BlackJoker, RedJoker, BlackJoker, RedJoker, New(Ace, Heart)}, nil},
+		{"Xs", nil, ErrInvalidCard},
 	}
 	for i, test := range tests {
 		hand, err := Parse(test.s)
@@ -210,6 +212,48 @@ func TestCardFormat(t *testing.T) {
 	}
 }
 
+func TestJokerFormat(t *testing.T) {
+	tests := []struct {
+		c   Card
+		exp string
+	}{
+		{BlackJoker, "Xb XB \"Xb\" X b 🃏 black joker Black Jokers black 52"},
+		{RedJoker, "Xr XR \"Xr\" X r 🂿 red joker Red Jokers red 53"},
+	}
+	for i, test := range tests {
+		c := test.c
+		if !c.Joker() {
+			t.Errorf("test %d expected joker", i)
+		}
+		if s := fmt.Sprintf("%s %S %q %r %u %c %n %P %t %d", c, c, c, c, c, c, c, c, c, c.Index()); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+		if v := FromString(c.String()); v != c {
+			t.Errorf("test %d expected %s, got: %s", i, c, v)
+		}
+		if v := FromIndex(c.Index()); v != c {
+			t.Errorf("test %d expected %s, got: %s", i, c, v)
+		}
+		buf, err := c.MarshalText()
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		var v Card
+		if err := v.UnmarshalText(buf); err != nil || v != c {
+			t.Errorf("test %d expected %s, got: %s (%v)", i, c, v, err)
+		}
+	}
+	if New(Ace, Spade).Joker() {
+		t.Errorf("expected As to not be a joker")
+	}
+	if n := len(DeckJoker.Unshuffled()); n != 53 {
+		t.Errorf("expected 53, got: %d", n)
+	}
+	if n := len(DeckJokers.Unshuffled()); n != 54 {
+		t.Errorf("expected 54, got: %d", n)
+	}
+}
+
 func contains(v []Card, c Card) bool {
 	for i := 0; i < len(v); i++ {
 		if v[i] == c {
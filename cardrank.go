@@ -3,6 +3,11 @@
 // hand ranks.
 package cardrank
 
+import (
+	"reflect"
+	"sort"
+)
+
 // HandRank is a poker hand rank.
 //
 // Ranks are ordered low-to-high.
@@ -28,9 +33,16 @@ const (
 	rankLowMax           HandRank = 16384
 )
 
+// FiveOfAKind is the fixed five of a kind hand rank, only possible with wild
+// cards. Wild hand ranks are relative to five of a kind, and are converted to
+// a fixed rank with WildRank.Fixed.
+const FiveOfAKind HandRank = 0
+
 // Fixed converts a relative poker rank to a fixed rank.
 func (r HandRank) Fixed() HandRank {
 	switch {
+	case r == FiveOfAKind:
+		return FiveOfAKind
 	case r <= StraightFlush:
 		return StraightFlush
 	case r <= FourOfAKind:
@@ -56,6 +68,8 @@ func (r HandRank) Fixed() HandRank {
 // String satisfies the fmt.Stringer interface.
 func (r HandRank) String() string {
 	switch r.Fixed() {
+	case FiveOfAKind:
+		return "Five of a Kind"
 	case StraightFlush:
 		return "Straight Flush"
 	case FourOfAKind:
@@ -81,6 +95,8 @@ func (r HandRank) String() string {
 // Name returns the hand rank name.
 func (r HandRank) Name() string {
 	switch r.Fixed() {
+	case FiveOfAKind:
+		return "FiveOfAKind"
 	case StraightFlush:
 		return "StraightFlush"
 	case FourOfAKind:
@@ -148,9 +164,433 @@ func RankLowAceFive(mask HandRank, c0, c1, c2, c3, c4 Card) HandRank {
 	return rank
 }
 
-// RankLowball is a Two-to-Seven low hand rank func.
+// RankLowball is a Two-to-Seven low hand rank func. Aces are high, and
+// straights and flushes count against the hand. A-2-3-4-5 is not a straight,
+// and ranks as a Ace-high hand (or Ace-high flush).
+//
+// Hands are ranked low-to-high, with 7-5-4-3-2 (the best hand) being ranked 1.
 func RankLowball(c0, c1, c2, c3, c4 Card) HandRank {
-	return rankMax - DefaultCactus(c0, c1, c2, c3, c4)
+	switch r := DefaultCactus(c0, c1, c2, c3, c4); {
+	case lowballAceNothing < r:
+		return rankMax - r
+	case r == lowballWheel:
+		return rankMax - lowballAceNothing
+	case lowballAceFlush < r:
+		return rankMax - r + 1
+	case r == lowballSteelWheel:
+		return rankMax - lowballAceFlush + 1
+	default:
+		return rankMax - r + 2
+	}
+}
+
+// RankLowAceSix is a Ace-to-Six low hand rank func. Aces are low, and
+// straights and flushes count against the hand. A-2-3-4-5 is a straight, and
+// K-Q-J-T-A is not.
+//
+// Hands are ranked low-to-high, with 6-4-3-2-A (the best hand) being ranked 1.
+func RankLowAceSix(c0, c1, c2, c3, c4 Card) HandRank {
+	return RankLowball(aceLow(c0), aceLow(c1), aceLow(c2), aceLow(c3), aceLow(c4))
+}
+
+// aceLow relabels the card's rank so that an Ace has the lowest rank (ie,
+// Two), moving all other ranks up by one. Used to rank Ace-to-Six hands as
+// Two-to-Seven hands.
+func aceLow(c Card) Card {
+	return New((c.Rank()+1)%13, c.Suit())
+}
+
+// aceHigh relabels the card's rank, reversing aceLow.
+func aceHigh(c Card) Card {
+	return New((c.Rank()+12)%13, c.Suit())
+}
+
+// Two-to-Seven rank values.
+const (
+	// lowballWheel is the Cactus Kev rank for 5-4-3-2-A (Straight).
+	lowballWheel HandRank = 1609
+	// lowballSteelWheel is the Cactus Kev rank for 5-4-3-2-A (Straight Flush).
+	lowballSteelWheel HandRank = 10
+	// lowballAceNothing is the Cactus Kev rank for A-6-4-3-2 (Nothing).
+	lowballAceNothing HandRank = 6678
+	// lowballAceFlush is the Cactus Kev rank for A-6-4-3-2 (Flush).
+	lowballAceFlush HandRank = 815
+)
+
+// lowballCactus converts a Two-to-Seven rank to a Cactus Kev rank with the
+// same fixed rank. A-5-4-3-2 converts to the rank of A-6-4-3-2.
+func lowballCactus(r HandRank) HandRank {
+	switch {
+	case r < rankMax-lowballAceNothing:
+		return rankMax - r
+	case r == rankMax-lowballAceNothing:
+		return lowballAceNothing
+	case r <= rankMax-lowballAceFlush:
+		return rankMax - r + 1
+	case r == rankMax-lowballAceFlush+1:
+		return lowballAceFlush
+	case r <= rankMax+1:
+		return rankMax - r + 2
+	}
+	return Invalid
+}
+
+// RankSoko is a Soko (Canadian Stud) hand rank func. Four-card flushes rank
+// above pairs and below two pair, and four-card straights rank just below
+// four-card flushes.
+func RankSoko(c0, c1, c2, c3, c4 Card) HandRank {
+	r := DefaultCactus(c0, c1, c2, c3, c4)
+	if r <= TwoPair {
+		return r
+	}
+	hand := []Card{c0, c1, c2, c3, c4}
+	if n, ok := sokoFlush(hand); ok {
+		return sokoFourFlush - n
+	}
+	if n, ok := sokoStraight(hand); ok {
+		return sokoFourStraight - n
+	}
+	return r + sokoShift
+}
+
+// Soko rank values.
+const (
+	// sokoFourFlush is the worst four-card flush rank, placed below two pair.
+	// There are 715 four-card flushes (13 choose 4), each with 13 kickers.
+	sokoFourFlush = TwoPair + 715*13
+	// sokoFourStraight is the worst Soko four-card straight rank (11 straight
+	// ranks, and 13 kickers).
+	sokoFourStraight = sokoFourFlush + 11*13
+	// sokoShift is the shift for the pair and nothing ranks.
+	sokoShift = sokoFourStraight - TwoPair
+)
+
+// sokoFlush returns the relative rank of a four-card flush (higher is
+// better), and true when the hand contains a four-card flush.
+func sokoFlush(hand []Card) (HandRank, bool) {
+	for _, s := range []Suit{Spade, Heart, Diamond, Club} {
+		var ranks []Rank
+		var kicker Rank
+		for _, c := range hand {
+			if c.Suit() == s {
+				ranks = append(ranks, c.Rank())
+			} else {
+				kicker = c.Rank()
+			}
+		}
+		if len(ranks) != 4 {
+			continue
+		}
+		sort.Slice(ranks, func(i, j int) bool {
+			return ranks[i] > ranks[j]
+		})
+		// colex index of the four ranks
+		n := binomial(int(ranks[0]), 4) + binomial(int(ranks[1]), 3) + binomial(int(ranks[2]), 2) + int(ranks[3])
+		return HandRank(n*13 + int(kicker)), true
+	}
+	return 0, false
+}
+
+// sokoStraight returns the relative rank of a four-card straight (higher is
+// better), and true when the hand contains a four-card straight.
+func sokoStraight(hand []Card) (HandRank, bool) {
+	var m [13]int
+	for _, c := range hand {
+		m[c.Rank()]++
+	}
+	for i := int(Ace); i >= int(Four); i-- {
+		// ace is low for a 4-3-2-A straight
+		j := i - 3
+		if i == int(Four) {
+			j = int(Ace)
+		}
+		if m[i] == 0 || m[i-1] == 0 || m[i-2] == 0 || m[j] == 0 {
+			continue
+		}
+		m[i], m[i-1], m[i-2], m[j] = m[i]-1, m[i-1]-1, m[i-2]-1, m[j]-1
+		kicker := int(Ace)
+		for ; 0 < kicker && m[kicker] == 0; kicker-- {
+		}
+		return HandRank((i-int(Four))*13 + kicker), true
+	}
+	return 0, false
+}
+
+// RankJokersWild is a wild card high hand rank func, where jokers can be any
+// card not in the hand.
+//
+// Wild hand ranks are ordered the same as regular hand ranks, but are shifted
+// to make room for five of a kind, ranked 1 (Aces) through 13 (Twos). A hand
+// with a wild card is otherwise ranked as its best possible hand. Use WildRank
+// to convert to a fixed rank.
+func RankJokersWild(c0, c1, c2, c3, c4 Card) HandRank {
+	r, _ := rankWild([]Card{c0, c1, c2, c3, c4}, Card.Joker, false)
+	return r
+}
+
+// RankDeucesWild is a wild card high hand rank func, where deuces (and
+// jokers) can be any card not in the hand. See RankJokersWild.
+func RankDeucesWild(c0, c1, c2, c3, c4 Card) HandRank {
+	r, _ := rankWild([]Card{c0, c1, c2, c3, c4}, deuceWild, false)
+	return r
+}
+
+// RankBug is a wild card high hand rank func, where jokers are a "bug" that
+// can only be an Ace, or a card completing a straight, flush, or straight
+// flush. Five Aces is the only possible five of a kind. See RankJokersWild.
+func RankBug(c0, c1, c2, c3, c4 Card) HandRank {
+	r, _ := rankWild([]Card{c0, c1, c2, c3, c4}, Card.Joker, true)
+	return r
+}
+
+// RankAceFiveBug is a Ace-to-Five low hand rank func, where jokers are a "bug"
+// that is the lowest rank not in the hand. See RankRazz.
+func RankAceFiveBug(c0, c1, c2, c3, c4 Card) HandRank {
+	v := aceFiveBug([]Card{c0, c1, c2, c3, c4})
+	return RankRazz(v[0], v[1], v[2], v[3], v[4])
+}
+
+// WildRank is a wild card hand rank, as returned by RankJokersWild,
+// RankDeucesWild, and RankBug.
+type WildRank HandRank
+
+// Fixed converts a relative wild rank to a fixed rank.
+func (r WildRank) Fixed() HandRank {
+	switch {
+	case HandRank(r) == Invalid:
+		return Invalid
+	case HandRank(r) <= wildShift:
+		return FiveOfAKind
+	}
+	return (HandRank(r) - wildShift).Fixed()
+}
+
+// String satisfies the fmt.Stringer interface.
+func (r WildRank) String() string {
+	return r.Fixed().String()
+}
+
+// Name returns the wild rank name.
+func (r WildRank) Name() string {
+	return r.Fixed().Name()
+}
+
+// wildShift is the shift for wild hand ranks, making room for the 13 five of
+// a kind ranks.
+const wildShift HandRank = 13
+
+// deuceWild returns true when the card is a deuce or a joker.
+func deuceWild(c Card) bool {
+	return c.Rank() == Two || c.Joker()
+}
+
+// rankWild returns the wild hand rank of the 5 cards, and the best cards
+// having the wild cards replaced with their assigned cards. Wild cards are
+// assigned any card not in the hand, or when bug is true, only an Ace or a
+// card completing a straight, flush, or straight flush. The best cards for
+// five of a kind contain the wild card left unassigned.
+//
+// Wild cards are assigned directly, for the best possible hand.
+func rankWild(hand []Card, wild func(Card) bool, bug bool) (HandRank, []Card) {
+	w := &wildHand{}
+	for _, c := range hand {
+		if wild(c) {
+			w.wild = append(w.wild, c)
+		} else {
+			w.add(c)
+		}
+	}
+	n := len(w.cards)
+	if n == 5 {
+		r := DefaultCactus(w.cards[0], w.cards[1], w.cards[2], w.cards[3], w.cards[4])
+		return r + wildShift, wildBest(r, w.cards)
+	}
+	// five of a kind
+	rank := w.high()
+	if w.counts[rank] == n && (!bug || rank == Ace) {
+		w.fill(rank, len(w.wild)-1)
+		sortHigh(w.cards)
+		return HandRank(Ace-rank) + 1, append(w.cards, w.wild[len(w.wild)-1])
+	}
+	// straight flush, flush, straight
+	best, v := Invalid, []Card(nil)
+	if w.suited() {
+		if b, ok := w.straight(w.cards[0].Suit()); ok {
+			r := DefaultCactus(b[0], b[1], b[2], b[3], b[4])
+			return r + wildShift, wildBest(r, b)
+		}
+		v = w.flush()
+	} else if b, ok := w.straight(Spade); ok {
+		v = b
+	}
+	if v != nil {
+		best = DefaultCactus(v[0], v[1], v[2], v[3], v[4])
+	}
+	// sets
+	var b []Card
+	if bug {
+		b = w.clone().fill(Ace, len(w.wild)).cards
+	} else {
+		b = w.sets().cards
+	}
+	if r := DefaultCactus(b[0], b[1], b[2], b[3], b[4]); r < best {
+		best, v = r, b
+	}
+	return best + wildShift, wildBest(best, v)
+}
+
+// wildHand is a hand's natural cards and wild cards.
+type wildHand struct {
+	cards  []Card
+	wild   []Card
+	counts [13]int
+	ranks  uint16
+}
+
+// add adds a natural card.
+func (w *wildHand) add(c Card) {
+	w.cards = append(w.cards, c)
+	w.counts[c.Rank()]++
+	w.ranks |= 1 << c.Rank()
+}
+
+// clone returns a copy of the wild hand.
+func (w *wildHand) clone() *wildHand {
+	v := *w
+	v.cards = append(make([]Card, 0, 5), w.cards...)
+	return &v
+}
+
+// high returns the highest natural rank, or Ace when there are no natural
+// cards.
+func (w *wildHand) high() Rank {
+	rank := Ace
+	for ; Two < rank && w.counts[rank] == 0 && w.ranks != 0; rank-- {
+	}
+	return rank
+}
+
+// fill adds k cards of the rank not in the hand, in suit order.
+func (w *wildHand) fill(rank Rank, k int) *wildHand {
+	for _, s := range []Suit{Spade, Heart, Diamond, Club} {
+		if c := New(rank, s); 0 < k && !hasCard(c, w.cards...) {
+			w.add(c)
+			k--
+		}
+	}
+	return w
+}
+
+// suited returns true when the natural cards are all the same suit.
+func (w *wildHand) suited() bool {
+	for _, c := range w.cards[1:] {
+		if c.Suit() != w.cards[0].Suit() {
+			return false
+		}
+	}
+	return true
+}
+
+// straight returns the highest straight containing the natural cards, with
+// the wild cards assigned the suit.
+func (w *wildHand) straight(suit Suit) ([]Card, bool) {
+	if bitCount(w.ranks) != len(w.cards) {
+		return nil, false
+	}
+	for high := Ace; Four < high; high-- {
+		m := uint16(1<<(high+1) - 1<<(high-4))
+		if high == Five {
+			m = 1<<Ace | 1<<(Five+1) - 1
+		}
+		if w.ranks&^m != 0 {
+			continue
+		}
+		v := append(make([]Card, 0, 5), w.cards...)
+		for r := Two; r <= Ace; r++ {
+			if m&^w.ranks&(1<<r) != 0 {
+				v = append(v, New(r, suit))
+			}
+		}
+		return v, true
+	}
+	return nil, false
+}
+
+// flush returns the highest flush, with the wild cards assigned the highest
+// ranks of the natural cards' suit not in the hand.
+func (w *wildHand) flush() []Card {
+	v := append(make([]Card, 0, 5), w.cards...)
+	for r := Ace; len(v) < 5; r-- {
+		if w.ranks&(1<<r) == 0 {
+			v = append(v, New(r, w.cards[0].Suit()))
+		}
+	}
+	return v
+}
+
+// sets returns the best four of a kind, full house, three of a kind, or pair,
+// with the wild cards assigned to the highest rank having the most natural
+// cards.
+func (w *wildHand) sets() *wildHand {
+	rank := w.high()
+	for r := rank; Two < r; r-- {
+		if w.counts[rank] < w.counts[r-1] {
+			rank = r - 1
+		}
+	}
+	return w.clone().fill(rank, len(w.wild))
+}
+
+// wildBest orders the best cards for the hand rank.
+func wildBest(r HandRank, hand []Card) []Card {
+	h := &Hand{HiRank: r}
+	bestHoldem(h, hand, Five)
+	return h.HiBest
+}
+
+// aceFiveBug returns a copy of the hand, with jokers assigned the lowest rank
+// (Aces low) not in the hand.
+func aceFiveBug(hand []Card) []Card {
+	v := make([]Card, len(hand))
+	var ranks uint16
+	for _, c := range hand {
+		if !c.Joker() {
+			ranks |= 1 << c.AceIndex()
+		}
+	}
+	for i, c := range hand {
+		if c.Joker() {
+			j := 0
+			for ; ranks&(1<<j) != 0; j++ {
+			}
+			ranks |= 1 << j
+			c = New(Rank((j+12)%13), Spade)
+		}
+		v[i] = c
+	}
+	return v
+}
+
+// hasCard returns true when v contains c.
+func hasCard(c Card, v ...Card) bool {
+	for _, d := range v {
+		if c == d {
+			return true
+		}
+	}
+	return false
+}
+
+// binomial returns n choose k.
+func binomial(n, k int) int {
+	if n < k {
+		return 0
+	}
+	r := 1
+	for i := 0; i < k; i++ {
+		r = r * (n - i) / (i + 1)
+	}
+	return r
 }
 
 // HandRankFunc ranks a hand of 5, 6, or 7 cards.
@@ -217,18 +657,35 @@ var (
 	cactus     RankFunc
 	cactusFast RankFunc
 	twoPlusTwo HandRankFunc
+
+	// packageRank is the package hand rank func (set in Init).
+	packageRank HandRankFunc
+
+	// Package part rank funcs, equivalent to the cactus and cactusFast rank
+	// funcs, but using the combined rank bits, suit bits, and prime product
+	// of a 5 card hand.
+	cactusParts     partsRankFunc
+	cactusFastParts partsRankFunc
 )
 
+// partsRankFunc is a 5 card hand rank func using the combined rank bits (ie,
+// the OR of the cards' rank bits), suit bits (ie, the AND of the cards' suit
+// bits), and prime product of the cards.
+type partsRankFunc func(bits, suits, product uint32) HandRank
+
 // Init inits the package level default variables. Must be manually called
 // prior to using this package when built with the `noinit` build tag.
 func Init() error {
 	switch {
 	case twoPlusTwo != nil && cactusFast != nil:
-		DefaultRank = NewHybrid(cactusFast, twoPlusTwo)
+		packageRank = NewHybrid(cactusFast, twoPlusTwo)
 	case cactusFast != nil:
-		DefaultRank = NewRankFunc(cactusFast)
+		packageRank = NewHybrid(cactusFast, NewCompact(cactusFast))
 	case cactus != nil:
-		DefaultRank = NewRankFunc(cactus)
+		packageRank = NewHybrid(cactus, NewCompact(cactus))
+	}
+	if packageRank != nil {
+		DefaultRank = defaultRank
 	}
 	switch {
 	case cactusFast != nil:
@@ -239,6 +696,18 @@ func Init() error {
 	return RegisterDefaultTypes()
 }
 
+// defaultRank is the package default hand rank func, set as DefaultRank by
+// Init.
+func defaultRank(hand []Card) HandRank {
+	return packageRank(hand)
+}
+
+// isDefaultRank returns true when DefaultRank is the package default hand rank
+// func (ie, has not been changed after Init).
+func isDefaultRank() bool {
+	return reflect.ValueOf(DefaultRank).Pointer() == reflect.ValueOf(defaultRank).Pointer()
+}
+
 // Error is a error.
 type Error string
 
@@ -257,6 +726,32 @@ const (
 	ErrInvalidCard Error = "invalid card"
 	// ErrInvalidType is the invalid type error.
 	ErrInvalidType Error = "invalid type"
+	// ErrInvalidPocket is the invalid pocket error.
+	ErrInvalidPocket Error = "invalid pocket"
+	// ErrInvalidBoard is the invalid board error.
+	ErrInvalidBoard Error = "invalid board"
+	// ErrDuplicateCard is the duplicate card error.
+	ErrDuplicateCard Error = "duplicate card"
+	// ErrNotEnoughCards is the not enough cards error.
+	ErrNotEnoughCards Error = "not enough cards"
+	// ErrInvalidDiscard is the invalid discard error.
+	ErrInvalidDiscard Error = "invalid discard"
+	// ErrInvalidDraw is the invalid draw error.
+	ErrInvalidDraw Error = "invalid draw"
+	// ErrInvalidRunouts is the invalid runouts error.
+	ErrInvalidRunouts Error = "invalid runouts"
+	// ErrInvalidFold is the invalid fold error.
+	ErrInvalidFold Error = "invalid fold"
+	// ErrInvalidCommitment is the invalid commitment error.
+	ErrInvalidCommitment Error = "invalid commitment"
+	// ErrInvalidDeck is the invalid deck error.
+	ErrInvalidDeck Error = "invalid deck"
+	// ErrInvalidDealer is the invalid dealer error.
+	ErrInvalidDealer Error = "invalid dealer"
+	// ErrInvalidRange is the invalid range error.
+	ErrInvalidRange Error = "invalid range"
+	// ErrInvalidShuffler is the invalid shuffler error.
+	ErrInvalidShuffler Error = "invalid shuffler"
 )
 
 // ordered is the ordered constraint.
@@ -272,6 +767,34 @@ func min[T ordered](a, b T) T {
 	return b
 }
 
+// combinations returns the combinations of taking n, choosing k, in the same
+// order as the t*c* tables. Each combination contains the k chosen indexes,
+// followed by the remaining unchosen indexes.
+func combinations(n, k int) [][]uint8 {
+	var v [][]uint8
+	var f func(int, []uint8)
+	f = func(start int, c []uint8) {
+		if len(c) == k {
+			u := make([]uint8, k, n)
+			copy(u, c)
+			for i, j := 0, 0; i < n; i++ {
+				if j < k && c[j] == uint8(i) {
+					j++
+					continue
+				}
+				u = append(u, uint8(i))
+			}
+			v = append(v, u)
+			return
+		}
+		for i := start; i < n; i++ {
+			f(i+1, append(c, uint8(i)))
+		}
+	}
+	f(0, make([]uint8, 0, k))
+	return v
+}
+
 // t4c2 is used for taking 4, choosing 2.
 var t4c2 = [6][4]uint8{
 	{0, 1, 2, 3},
@@ -55,6 +55,184 @@ func TestEvalRank(t *testing.T) {
 	}
 }
 
+func TestWild(t *testing.T) {
+	tests := []struct {
+		typ Type
+		v   string
+		exp string
+	}{
+		{JokersWild, "Ah Ad Xr Ac As", "Five of a Kind, Aces [Ac Ad Ah As Xr]"},
+		{JokersWild, "Xb Xr 7c 7h 9c", "Four of a Kind, Sevens, kicker Nine [7c 7d 7h 7s 9c]"},
+		{JokersWild, "Xb 7c 7h 9c 9h", "Full House, Nines full of Sevens [9c 9h 9s 7c 7h]"},
+		{JokersWild, "Xb Xr 7c 8c 9c", "Straight Flush, Jack-high [Jc Tc 9c 8c 7c]"},
+		{JokersWild, "Kh Qh Jh Th Xr", "Straight Flush, Ace-high, Royal [Ah Kh Qh Jh Th]"},
+		{JokersWild, "Kh Qh 2h 3c Xr", "Pair, Kings, kickers Queen, Three, Two [Kh Ks Qh 3c 2h]"},
+		{JokersWild, "Kh Qh 2h 3c 5d", "Nothing, King-high, kickers Queen, Five, Three, Two [Kh Qh 5d 3c 2h]"},
+		{DeucesWild, "2h 2d 2c 2s 7c", "Five of a Kind, Sevens [7c 7d 7h 7s 2s]"},
+		{DeucesWild, "2c 2d 2h 2s Ah", "Five of a Kind, Aces [Ac Ad Ah As 2s]"},
+		{DeucesWild, "2h 2d 9c Ks 7c", "Three of a Kind, Kings, kickers Nine, Seven [Kd Kh Ks 9c 7c]"},
+		{DeucesWild, "2h 8h 6h 5h 4h", "Straight Flush, Eight-high [8h 7h 6h 5h 4h]"},
+		{DrawBug, "Ah Xb Ad Ac As", "Five of a Kind, Aces [Ac Ad Ah As Xb]"},
+		{DrawBug, "Kh Kd Kc Xb As", "Full House, Kings full of Aces [Kc Kd Kh Ah As]"},
+		{DrawBug, "Kh Kd Kc Ks Xb", "Four of a Kind, Kings, kicker Ace [Kc Kd Kh Ks As]"},
+		{DrawBug, "Kh Qd Jc 9s Xb", "Straight, King-high [Kh Qd Jc Ts 9s]"},
+		{DrawBug, "Kh 8h 6h 9h Xb", "Flush, Ace-high [Ah Kh 9h 8h 6h]"},
+		{DrawBug, "Kh 8c 6h 9h Xb", "Nothing, Ace-high, kickers King, Nine, Eight, Six [As Kh 9h 8c 6h]"},
+		{DrawBug, "Kh Kc 6h 9h Xb", "Pair, Kings, kickers Ace, Nine, Six [Kc Kh As 9h 6h]"},
+		{California, "Xb 2c 3d 4h 5s", "Five, Four, Three, Two, Ace-low [5s 4h 3d 2c As]"},
+		{California, "Xb Ac 3d 4h 5s", "Five, Four, Three, Two, Ace-low [5s 4h 3d 2s Ac]"},
+		{California, "Xb Ac Ad 4h 5s", "Pair, Aces, kickers Five, Four, Two [Ac Ad 5s 4h 2s]"},
+	}
+	for i, test := range tests {
+		h := NewHand(test.typ, Must(test.v), nil)
+		if s := fmt.Sprintf("%s", h); s != test.exp {
+			t.Errorf("test %d %s expected %q, got: %q", i, test.typ, test.exp, s)
+		}
+		for j, c := range h.HiBest {
+			if contains(h.HiBest[j+1:], c) {
+				t.Errorf("test %d %s expected no duplicate cards, got: %v", i, test.typ, h.HiBest)
+			}
+		}
+	}
+	if r := NewHand(JokersWild, Must("Ah Ad Xr Ac As"), nil).Fixed(); r != FiveOfAKind {
+		t.Errorf("expected %s, got: %s", FiveOfAKind, r)
+	}
+	if s, exp := WildRank(NewHand(DeucesWild, Must("2c 2d 2h 2s Ah"), nil).HiRank).String(), "Five of a Kind"; s != exp {
+		t.Errorf("expected %q, got: %q", exp, s)
+	}
+	if s, exp := WildRank(NewHand(DeucesWild, Must("2c 7d 8d 9d Td"), nil).HiRank).String(), "Straight Flush"; s != exp {
+		t.Errorf("expected %q, got: %q", exp, s)
+	}
+	// a joker always ranks at least as well as the bug, and a hand without
+	// jokers ranks the same as a regular hand
+	r := rand.New(rand.NewSource(0))
+	d := DeckJokers.New()
+	for i := 0; i < 1000; i++ {
+		d.Shuffle(r)
+		v := d.Draw(7)
+		wild, bug := NewHand(JokersWild, v[:2], v[2:]), NewHand(DrawBug, v[:2], v[2:])
+		if bug.HiRank < wild.HiRank {
+			t.Errorf("test %d %s expected %d <= %d", i, v, wild.HiRank, bug.HiRank)
+		}
+		if !contains(v, BlackJoker) && !contains(v, RedJoker) {
+			if exp := NewHand(Holdem, v[:2], v[2:]); wild.HiRank != exp.HiRank+wildShift || wild.Description() != exp.Description() {
+				t.Errorf("test %d %s expected %s, got: %s", i, v, exp, wild)
+			}
+		}
+		d.Reset()
+	}
+}
+
+func TestRankWild(t *testing.T) {
+	tests := []struct {
+		name string
+		wild func(Card) bool
+		bug  bool
+	}{
+		{"jokers", Card.Joker, false},
+		{"deuces", deuceWild, false},
+		{"bug", Card.Joker, true},
+	}
+	r := rand.New(rand.NewSource(0))
+	d := DeckJokers.New()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i := 0; i < 2000; i++ {
+				d.Shuffle(r)
+				v := d.Draw(5)
+				// force wild cards into some hands
+				if i%2 == 0 {
+					v[0], v[i%5] = RedJoker, New(Two, Heart)
+				}
+				if hasDuplicate(v) {
+					d.Reset()
+					continue
+				}
+				rank, best := rankWild(v, test.wild, test.bug)
+				exp, _ := bruteWild(v, test.wild, test.bug)
+				if rank != exp {
+					t.Errorf("test %d %v expected %d, got: %d", i, v, exp, rank)
+				}
+				if len(best) != 5 || hasDuplicate(best) {
+					t.Errorf("test %d %v expected 5 unique best cards, got: %v", i, v, best)
+				}
+				if wildShift < rank {
+					if r := DefaultCactus(best[0], best[1], best[2], best[3], best[4]) + wildShift; r != rank {
+						t.Errorf("test %d %v expected best %v to rank %d, got: %d", i, v, best, rank, r)
+					}
+				}
+				d.Reset()
+			}
+		})
+	}
+}
+
+// bruteWild is the brute force wild hand rank, assigning the wild cards every
+// combination of the cards not in the hand.
+func bruteWild(hand []Card, wild func(Card) bool, bug bool) (HandRank, []Card) {
+	b := make([]Card, 0, 5)
+	for _, c := range hand {
+		if !wild(c) {
+			b = append(b, c)
+		}
+	}
+	n := len(b)
+	if n == 5 {
+		return DefaultCactus(b[0], b[1], b[2], b[3], b[4]) + wildShift, b
+	}
+	// five of a kind
+	rank, five := Ace, true
+	if n != 0 {
+		rank = b[0].Rank()
+	}
+	for _, c := range b {
+		five = five && c.Rank() == rank
+	}
+	if five && (!bug || rank == Ace) {
+		return HandRank(Ace-rank) + 1, nil
+	}
+	// assign the wild cards every combination of the remaining cards
+	v := make([]Card, 0, 52)
+	for _, c := range unshuffledFrench {
+		if !hasCard(c, b[:n]...) {
+			v = append(v, c)
+		}
+	}
+	best, v5 := Invalid, make([]Card, 5)
+	b = b[:5]
+	var f func(int, int)
+	f = func(i, j int) {
+		if i == 5 {
+			if r := DefaultCactus(b[0], b[1], b[2], b[3], b[4]); r < best && (!bug || bruteBugValid(r, b[n:])) {
+				best = r
+				copy(v5, b)
+			}
+			return
+		}
+		for ; j < len(v); j++ {
+			b[i] = v[j]
+			f(i+1, j+1)
+		}
+	}
+	f(n, 0)
+	return best + wildShift, v5
+}
+
+// bruteBugValid returns true when the bug cards are all Aces, or when the hand
+// rank is a straight, flush, or straight flush.
+func bruteBugValid(r HandRank, bugs []Card) bool {
+	switch r.Fixed() {
+	case StraightFlush, Flush, Straight:
+		return true
+	}
+	for _, c := range bugs {
+		if c.Rank() != Ace {
+			return false
+		}
+	}
+	return true
+}
+
 func TestRankEightOrBetter(t *testing.T) {
 	p0 := Must("Ah 2h 3h 4h 5h 6h 7h 8h")
 	for i := Nine; i <= King; i++ {
@@ -111,6 +289,28 @@ func TestAllCards(t *testing.T) {
 	}
 }
 
+func TestCompact(t *testing.T) {
+	if cactus == nil {
+		t.Logf("skipping: Cactus is not available")
+		return
+	}
+	f, g := NewRankFunc(cactus), NewCompact(cactus)
+	r := rand.New(rand.NewSource(0))
+	for n := 5; n <= 7; n++ {
+		for i := 0; i < 100000; i++ {
+			d := NewDeck()
+			d.Shuffle(r)
+			hand := d.Draw(n)
+			if exp, rank := f(hand), g(hand); rank != exp {
+				t.Fatalf("test %d %d %b expected %d, got: %d", n, i, hand, exp, rank)
+			}
+		}
+	}
+	if r := g(Must("Ah Kh")); r != Invalid {
+		t.Errorf("expected %d, got: %d", Invalid, r)
+	}
+}
+
 var allCards []Card
 
 func init() {
@@ -215,6 +415,9 @@ func evalTests(base bool) []evalTest {
 	if twoPlusTwo != nil {
 		tests = append(tests, evalTest{"TwoPlusTwo", twoPlusTwo})
 	}
+	if cactus != nil {
+		tests = append(tests, evalTest{"Compact", NewCompact(cactus)})
+	}
 	if cactusFast != nil && twoPlusTwo != nil {
 		tests = append(tests, evalTest{"Hybrid", NewHybrid(cactusFast, twoPlusTwo)})
 	}
@@ -0,0 +1,319 @@
+package cardrank
+
+import (
+	"strings"
+)
+
+// HandFlag is a made hand or draw classification flag.
+type HandFlag uint16
+
+// Hand flags.
+const (
+	// FlagOverpair is a pocket pair higher than every board card.
+	FlagOverpair HandFlag = 1 << iota
+	// FlagTopPair is a pocket card paired with the highest board card.
+	FlagTopPair
+	// FlagTopPairTopKicker is top pair with the highest possible kicker.
+	FlagTopPairTopKicker
+	// FlagSet is a pocket pair paired with a board card.
+	FlagSet
+	// FlagTrips is a pocket card paired with a board pair.
+	FlagTrips
+	// FlagFlushDraw is four cards of a suit.
+	FlagFlushDraw
+	// FlagNutFlushDraw is a flush draw with the highest unseen card of the
+	// suit.
+	FlagNutFlushDraw
+	// FlagOpenEnded is four consecutive ranks, completed by either end.
+	FlagOpenEnded
+	// FlagGutshot is a straight draw completed by a single rank.
+	FlagGutshot
+	// FlagDoubleGutter is a straight draw completed by two ranks, without
+	// four consecutive ranks.
+	FlagDoubleGutter
+	// FlagWrap is a straight draw completed by three or more ranks.
+	FlagWrap
+	// FlagBackdoorFlush is three cards of a suit on the flop.
+	FlagBackdoorFlush
+	// FlagBackdoorStraight is three ranks of a possible straight on the flop.
+	FlagBackdoorStraight
+)
+
+// handFlagNames are the hand flag names.
+var handFlagNames = []string{
+	"Overpair",
+	"TopPair",
+	"TopPairTopKicker",
+	"Set",
+	"Trips",
+	"FlushDraw",
+	"NutFlushDraw",
+	"OpenEnded",
+	"Gutshot",
+	"DoubleGutter",
+	"Wrap",
+	"BackdoorFlush",
+	"BackdoorStraight",
+}
+
+// String satisfies the fmt.Stringer interface.
+func (flag HandFlag) String() string {
+	var v []string
+	for i, name := range handFlagNames {
+		if flag&(1<<i) != 0 {
+			v = append(v, name)
+		}
+	}
+	return strings.Join(v, "|")
+}
+
+// Draw returns true when the flag contains a straight or flush draw.
+func (flag HandFlag) Draw() bool {
+	return flag&(FlagFlushDraw|FlagOpenEnded|FlagGutshot|FlagDoubleGutter|FlagWrap) != 0
+}
+
+// HandClass is a hand's classification for a street.
+type HandClass struct {
+	// Board is the count of board cards.
+	Board int
+	// HiRank is the hand's hi rank.
+	HiRank HandRank
+	// Fixed is the hand's fixed hi rank (see HandRank.Fixed).
+	Fixed HandRank
+	// Flags are the made hand and draw flags.
+	Flags HandFlag
+}
+
+// Classify classifies the hand's pocket and board for each street, starting
+// with the flop (the first 3 board cards). Draws are only classified before
+// the river, and backdoor draws only on the flop.
+//
+// Holdem hands may use any of the pocket cards, while Omaha hands must use
+// exactly 2 pocket cards and 3 board cards, as with NewOmahaEval. Returns
+// ErrInvalidType for other types, ErrInvalidPocket when the pocket has fewer
+// than 2 cards, and ErrInvalidBoard when the board has fewer than 3, or more
+// than 5, cards.
+func (h *Hand) Classify() ([]HandClass, error) {
+	desc, ok := descs[h.Type]
+	var omaha bool
+	switch {
+	case !ok:
+		return nil, ErrInvalidType
+	case desc.Eval == EvalOmaha, desc.Eval == EvalOmahaFive, desc.Eval == EvalOmahaSix:
+		omaha = true
+	case desc.Eval != EvalHoldem && desc.Eval != EvalShort && desc.Eval != EvalManila:
+		return nil, ErrInvalidType
+	}
+	switch {
+	case len(h.Pocket) < 2:
+		return nil, ErrInvalidPocket
+	case len(h.Board) < 3, 5 < len(h.Board):
+		return nil, ErrInvalidBoard
+	}
+	// determine straight windows for the deck's lowest rank
+	low := Ace
+	for _, c := range desc.Deck.Unshuffled() {
+		if !c.Joker() && c.Rank() < low {
+			low = c.Rank()
+		}
+	}
+	windows := []uint16{1<<Ace | (1<<(low+4) - 1<<low)}
+	for r := low; r+4 <= Ace; r++ {
+		windows = append(windows, 1<<(r+5)-1<<r)
+	}
+	var v []HandClass
+	for n := 3; n <= len(h.Board); n++ {
+		c := &classifier{
+			pocket:  h.Pocket,
+			board:   h.Board[:n],
+			omaha:   omaha,
+			windows: windows,
+		}
+		hand := NewHand(h.Type, h.Pocket, c.board)
+		fixed := hand.Fixed()
+		flags := c.made()
+		if n < 5 {
+			flags |= c.draws(fixed, n == 3)
+		}
+		v = append(v, HandClass{
+			Board:  n,
+			HiRank: hand.HiRank,
+			Fixed:  fixed,
+			Flags:  flags,
+		})
+	}
+	return v, nil
+}
+
+// classifier classifies a pocket and board.
+type classifier struct {
+	pocket  []Card
+	board   []Card
+	omaha   bool
+	windows []uint16
+}
+
+// made returns the made hand flags, for each pair of pocket cards.
+func (c *classifier) made() HandFlag {
+	var counts [13]int
+	top, seen := Two, uint16(0)
+	for _, card := range c.board {
+		counts[card.Rank()]++
+		seen |= 1 << card.Rank()
+		if top < card.Rank() {
+			top = card.Rank()
+		}
+	}
+	// the highest possible kicker is the highest rank not on the board
+	kicker := Ace
+	for ; Two < kicker && seen&(1<<kicker) != 0; kicker-- {
+	}
+	var flags HandFlag
+	for _, v := range combinations(len(c.pocket), 2) {
+		a, b := c.pocket[v[0]].Rank(), c.pocket[v[1]].Rank()
+		switch {
+		case a == b && top < a:
+			flags |= FlagOverpair
+		case a == b && counts[a] == 1:
+			flags |= FlagSet
+		case a != b && counts[a] == 2, a != b && counts[b] == 2:
+			flags |= FlagTrips
+		case a != b && (a == top || b == top) && counts[top] == 1:
+			if a != top {
+				a, b = b, a
+			}
+			flags |= FlagTopPair
+			if b == kicker {
+				flags |= FlagTopPairTopKicker
+			}
+		}
+	}
+	return flags
+}
+
+// draws returns the draw flags, when the hand is not already a straight or
+// flush.
+func (c *classifier) draws(fixed HandRank, flop bool) HandFlag {
+	var flags HandFlag
+	// flush draws
+	if fixed > Flush {
+		// the highest unseen rank of each suit
+		var nuts [4]Rank
+		for i, suit := range []Suit{Spade, Heart, Diamond, Club} {
+			for nuts[i] = Ace; hasCard(New(nuts[i], suit), c.board...); nuts[i]-- {
+			}
+		}
+		for _, v := range c.sets(4) {
+			if suited(v) {
+				flags |= FlagFlushDraw
+				for _, card := range v {
+					if hasCard(card, c.pocket...) && card.Rank() == nuts[card.SuitIndex()] {
+						flags |= FlagNutFlushDraw
+					}
+				}
+			}
+		}
+		if flop && flags&FlagFlushDraw == 0 {
+			for _, v := range c.sets(3) {
+				if suited(v) {
+					flags |= FlagBackdoorFlush
+				}
+			}
+		}
+	}
+	// straight draws
+	if fixed > Straight {
+		var outs uint16
+		open := false
+		for _, v := range c.sets(4) {
+			m := rankMask(v)
+			n := 0
+			for _, w := range c.windows {
+				if m&w == m && bitCount(m) == 4 {
+					outs |= w &^ m
+					n++
+				}
+			}
+			open = open || n == 2
+		}
+		switch n := bitCount(outs); {
+		case 3 <= n:
+			flags |= FlagWrap
+		case n == 2 && open:
+			flags |= FlagOpenEnded
+		case n == 2:
+			flags |= FlagDoubleGutter
+		case n == 1:
+			flags |= FlagGutshot
+		}
+		if flop && outs == 0 {
+			for _, v := range c.sets(3) {
+				m := rankMask(v)
+				for _, w := range c.windows {
+					if m&w == m && bitCount(m) == 3 {
+						flags |= FlagBackdoorStraight
+					}
+				}
+			}
+		}
+	}
+	return flags
+}
+
+// sets returns the sets of k cards usable in a hand. Holdem sets contain at
+// least one pocket card, and Omaha sets contain exactly 2 pocket cards.
+func (c *classifier) sets(k int) [][]Card {
+	var sets [][]Card
+	if c.omaha {
+		for _, p := range combinations(len(c.pocket), 2) {
+			for _, b := range combinations(len(c.board), k-2) {
+				v := []Card{c.pocket[p[0]], c.pocket[p[1]]}
+				for _, i := range b[:k-2] {
+					v = append(v, c.board[i])
+				}
+				sets = append(sets, v)
+			}
+		}
+		return sets
+	}
+	cards := append(append([]Card(nil), c.pocket...), c.board...)
+	for _, u := range combinations(len(cards), k) {
+		v := make([]Card, k)
+		pocket := false
+		for i, j := range u[:k] {
+			v[i], pocket = cards[j], pocket || int(j) < len(c.pocket)
+		}
+		if pocket {
+			sets = append(sets, v)
+		}
+	}
+	return sets
+}
+
+// suited returns true when the cards are all the same suit.
+func suited(v []Card) bool {
+	for _, c := range v[1:] {
+		if c.Suit() != v[0].Suit() {
+			return false
+		}
+	}
+	return true
+}
+
+// rankMask returns the rank bit mask of the cards.
+func rankMask(v []Card) uint16 {
+	var m uint16
+	for _, c := range v {
+		m |= 1 << c.Rank()
+	}
+	return m
+}
+
+// bitCount returns the count of set bits.
+func bitCount(m uint16) int {
+	n := 0
+	for ; m != 0; m &= m - 1 {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,80 @@
+package cardrank
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		typ    Type
+		pocket string
+		board  string
+		exp    []string
+	}{
+		{Holdem, "Ah Kh", "2h 7h 9c Td Qs", []string{"FlushDraw|NutFlushDraw", "FlushDraw|NutFlushDraw", ""}},
+		{Holdem, "Qh Kh", "2h 7h As", []string{"FlushDraw|BackdoorStraight"}},
+		{Holdem, "Jh Th", "9c 8d 2s Ah Qs", []string{"OpenEnded", "OpenEnded", ""}},
+		{Holdem, "Ac Kd", "Ks 7h 2c", []string{"TopPair|TopPairTopKicker"}},
+		{Holdem, "Kc Qd", "Ks 7h 2c", []string{"TopPair"}},
+		{Holdem, "Qs Qd", "Js 7h 2c", []string{"Overpair"}},
+		{Holdem, "7s 7d", "7h Kc 2d", []string{"Set"}},
+		{Holdem, "Ks 7d", "7h 7c 2d", []string{"Trips"}},
+		{Holdem, "Ks 7d", "7h 7c 7s", []string{""}},
+		{Holdem, "9s 7d", "Jc 5h 8c", []string{"DoubleGutter"}},
+		{Holdem, "9s 8d", "Qc Jh 2c", []string{"Gutshot"}},
+		{Holdem, "9s 8s", "Qs 4h 2c", []string{"BackdoorFlush|BackdoorStraight"}},
+		{Holdem, "9s 8d", "Th 4h 2c", []string{"BackdoorStraight"}},
+		{Holdem, "Ah 2c", "Kh Qh Jh", []string{"FlushDraw|NutFlushDraw|Gutshot"}},
+		{Short, "Ah 9d", "6c 7s Kd", []string{"Gutshot"}},
+		{Omaha, "Ah 2c 3d 4s", "Kh Qh Jh", []string{""}},
+		{Omaha, "Ah Kh Qd Jc", "Th 9h 2s", []string{"FlushDraw|NutFlushDraw|Wrap"}},
+		{Omaha, "As Kd 7c 2h", "Ks 8h 3c", []string{"TopPair|TopPairTopKicker|BackdoorStraight"}},
+		{Omaha, "Qs Qd Jc Jh", "Js Th 2c 9d", []string{"Overpair|TopPair|Set|BackdoorStraight", "Overpair|TopPair|Set|OpenEnded"}},
+	}
+	for i, test := range tests {
+		board := Must(test.board)
+		v, err := test.typ.RankHand(Must(test.pocket), board).Classify()
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if len(v) != len(test.exp) {
+			t.Fatalf("test %d expected %d streets, got: %d", i, len(test.exp), len(v))
+		}
+		for j, class := range v {
+			if s := class.Flags.String(); s != test.exp[j] {
+				t.Errorf("test %d street %d expected %q, got: %q", i, j, test.exp[j], s)
+			}
+			exp := test.typ.RankHand(Must(test.pocket), board[:class.Board])
+			if class.Board != j+3 || class.HiRank != exp.HiRank || class.Fixed != exp.Fixed() {
+				t.Errorf("test %d street %d expected %d %d %s, got: %d %d %s", i, j, j+3, exp.HiRank, exp.Fixed(), class.Board, class.HiRank, class.Fixed)
+			}
+		}
+	}
+}
+
+func TestClassifyErrors(t *testing.T) {
+	tests := []struct {
+		typ    Type
+		pocket string
+		board  string
+		err    error
+	}{
+		{Stud, "Ah As Kh Ks", "", ErrInvalidType},
+		{Razz, "Ah As Kh Ks", "", ErrInvalidType},
+		{Holdem, "Ah As Kh Ks", "2h 3h", ErrInvalidBoard},
+		{Omaha, "Ah As Kh Ks", "2h 3h 4h 5h 6h 7h", ErrInvalidBoard},
+		{Holdem, "Ah", "2h 3h 4h", ErrInvalidPocket},
+		{Omaha, "", "2h 3h 4h", ErrInvalidPocket},
+	}
+	for i, test := range tests {
+		h := &Hand{
+			Type:   test.typ,
+			Pocket: Must(test.pocket),
+			Board:  Must(test.board),
+		}
+		if _, err := h.Classify(); !errors.Is(err, test.err) {
+			t.Errorf("test %d expected error %v, got: %v", i, test.err, err)
+		}
+	}
+}
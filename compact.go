@@ -0,0 +1,126 @@
+package cardrank
+
+import (
+	"math/bits"
+)
+
+// NewCompact creates a new compact hand rank eval func for 5, 6, or 7 cards,
+// generating its lookup tables using f.
+//
+// The Compact eval determines a hand's rank by first checking for a flush
+// suit, looking up the suit's 13-bit rank mask in a flush table. For all other
+// hands, a perfect hash of the hand's rank counts is used to look up the rank
+// in a rank table. Combined, the tables are approximately 160 KiB, providing
+// fast 7 card hand lookup for builds where the TwoPlusTwo is not available
+// (ie, when using the 'portable' or 'embedded' build tags). Uses Cactus Kev
+// values.
+func NewCompact(f RankFunc) HandRankFunc {
+	h, eval := newCompactHash(), NewRankFunc(f)
+	// build flush table
+	flushes := make([]HandRank, 1<<13)
+	for mask := 0; mask < len(flushes); mask++ {
+		if n := bits.OnesCount(uint(mask)); n < 5 || 7 < n {
+			continue
+		}
+		var hand []Card
+		for r := Two; r <= Ace; r++ {
+			if mask&(1<<r) != 0 {
+				hand = append(hand, New(r, Spade))
+			}
+		}
+		flushes[mask] = eval(hand)
+	}
+	// build rank table, assigning suits in order so no hand has a flush
+	ranks := make([]HandRank, h.base[len(h.base)-1])
+	suits := [4]Suit{Spade, Heart, Diamond, Club}
+	var counts [13]uint8
+	var g func(Rank, int)
+	g = func(r Rank, n int) {
+		if r > Ace {
+			if 5 <= n {
+				var hand []Card
+				for i := Two; i <= Ace; i++ {
+					for j := uint8(0); j < counts[i]; j++ {
+						hand = append(hand, New(i, suits[len(hand)%4]))
+					}
+				}
+				ranks[h.index(&counts, n)] = eval(hand)
+			}
+			return
+		}
+		for c := 0; c <= 4 && n+c <= 7; c++ {
+			counts[r] = uint8(c)
+			g(r+1, n+c)
+		}
+		counts[r] = 0
+	}
+	g(Two, 0)
+	return func(hand []Card) HandRank {
+		n := len(hand)
+		if n < 5 || 7 < n {
+			return Invalid
+		}
+		var counts [13]uint8
+		var suits [9]uint8
+		var masks [9]uint16
+		for _, c := range hand {
+			r, s := c>>8&0xf, c>>12&0xf
+			counts[r]++
+			suits[s]++
+			masks[s] |= uint16(c >> 16)
+		}
+		for s := Spade; s <= Club; s <<= 1 {
+			if 5 <= suits[s] {
+				return flushes[masks[s]]
+			}
+		}
+		return ranks[h.index(&counts, n)]
+	}
+}
+
+// compactHash is a perfect hash of rank counts for 5, 6, or 7 cards.
+type compactHash struct {
+	// off are the offsets for a rank, remaining card count, and rank count.
+	off [13][8][5]uint32
+	// base are the base offsets for each total card count (5, 6, 7, and the
+	// table size).
+	base [4]uint32
+}
+
+// newCompactHash creates a new compact hash.
+func newCompactHash() *compactHash {
+	// dp[r][k] is the number of ways to distribute k cards across ranks r
+	// through Ace, with no more than 4 cards of any rank
+	var dp [14][8]uint32
+	dp[13][0] = 1
+	for r := 12; r >= 0; r-- {
+		for k := 0; k < 8; k++ {
+			for c := 0; c <= 4 && c <= k; c++ {
+				dp[r][k] += dp[r+1][k-c]
+			}
+		}
+	}
+	h := new(compactHash)
+	for r := 0; r < 13; r++ {
+		for k := 0; k < 8; k++ {
+			for c := 1; c <= 4 && c <= k; c++ {
+				h.off[r][k][c] = h.off[r][k][c-1] + dp[r+1][k-c+1]
+			}
+		}
+	}
+	for i := 1; i < 4; i++ {
+		h.base[i] = h.base[i-1] + dp[0][i+4]
+	}
+	return h
+}
+
+// index returns the hash index for the rank counts of n cards.
+func (h *compactHash) index(counts *[13]uint8, n int) uint32 {
+	i := h.base[n-5]
+	for r := 0; r < 13 && n != 0; r++ {
+		c := counts[r]
+		i += h.off[r][n][c]
+		n -= int(c)
+	}
+	return i
+}
@@ -1,7 +1,10 @@
 package cardrank
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -12,6 +15,9 @@ type Shuffler interface {
 }
 
 // DeckType is a deck type.
+//
+// The French derived deck types are the lowest card rank kept in the deck.
+// Additional deck types can be registered with RegisterDeck.
 type DeckType uint8
 
 // Deck types.
@@ -24,62 +30,56 @@ const (
 	DeckManila = DeckType(Seven)
 	// DeckRoyal is a deck of Royal (10+) cards.
 	DeckRoyal = DeckType(Ten)
+	// DeckJoker is a deck of French (52) cards and a black joker.
+	DeckJoker = DeckType(jokerRank)
+	// DeckJokers is a deck of French (52) cards and a black and red joker.
+	DeckJokers = DeckType(jokerRank + 1)
 )
 
 // String satisfies the fmt.Stringer interface.
 func (typ DeckType) String() string {
-	switch typ {
-	case DeckFrench:
-		return "French"
-	case DeckShort:
-		return "Short"
-	case DeckManila:
-		return "Manila"
-	case DeckRoyal:
-		return "Royal"
-	}
-	return ""
+	return decks[typ].Name
+}
+
+// Desc returns the deck type description.
+func (typ DeckType) Desc() DeckDesc {
+	return decks[typ]
 }
 
-// Unshuffled returns a set of unshuffled cards for
+// Unshuffled returns a set of unshuffled cards for the deck type.
 func (typ DeckType) Unshuffled() []Card {
-	switch typ {
-	case DeckFrench, DeckShort, DeckManila, DeckRoyal:
-		v := make([]Card, 4*(Ace-Rank(typ)+1))
-		var i int
-		for _, s := range []Suit{Spade, Heart, Diamond, Club} {
-			for r := Rank(typ); r <= Ace; r++ {
-				v[i] = New(r, s)
-				i++
-			}
-		}
-		return v
+	desc, ok := decks[typ]
+	if !ok {
+		return nil
 	}
-	return nil
+	v := make([]Card, len(desc.Cards))
+	copy(v, desc.Cards)
+	return v
 }
 
 // New returns a new deck for the deck type.
 func (typ DeckType) New() *Deck {
-	var v []Card
-	switch typ {
-	case DeckFrench:
-		v = unshuffledFrench
-	case DeckShort:
-		v = unshuffledShort
-	case DeckManila:
-		v = unshuffledManila
-	case DeckRoyal:
-		v = unshuffledRoyal
-	default:
+	v := typ.Unshuffled()
+	if v == nil {
 		return nil
 	}
-	n := len(v)
-	d := &Deck{
-		v: make([]Card, n),
-		l: n,
+	return &Deck{
+		v: v,
+		l: len(v),
+	}
+}
+
+// Without returns a new deck for the deck type, with the cards removed. See
+// Deck.Remove.
+func (typ DeckType) Without(cards ...Card) (*Deck, error) {
+	d := typ.New()
+	if d == nil {
+		return nil, ErrInvalidType
+	}
+	if err := d.Remove(cards...); err != nil {
+		return nil, err
 	}
-	copy(d.v, v)
-	return d
+	return d, nil
 }
 
 // unshuffled cards.
@@ -90,7 +90,68 @@ var (
 	unshuffledRoyal  []Card
 )
 
+// DeckDesc is a deck type description.
+type DeckDesc struct {
+	// Type is the deck type.
+	Type DeckType
+	// Name is the deck name.
+	Name string
+	// Cards are the deck's unshuffled cards. Cards are limited to the French
+	// ranks and suits (see New), and jokers. A card may appear more than once.
+	// Decks with a fifth suit are not supported.
+	Cards []Card
+}
+
+// decks are the registered deck type descriptions.
+var decks map[DeckType]DeckDesc = make(map[DeckType]DeckDesc)
+
+// RegisterDeck registers a deck type description, allowing the deck type to
+// be used with type descriptions (see TypeDesc). Returns ErrInvalidId when the
+// deck type is already registered, ErrInvalidDeck when the deck has no cards,
+// or ErrInvalidCard when a card is invalid.
+func RegisterDeck(desc DeckDesc) error {
+	if _, ok := decks[desc.Type]; ok {
+		return ErrInvalidId
+	}
+	if len(desc.Cards) == 0 {
+		return ErrInvalidDeck
+	}
+	for _, c := range desc.Cards {
+		if c == InvalidCard || (!c.Joker() && New(c.Rank(), c.Suit()) != c) {
+			return ErrInvalidCard
+		}
+	}
+	v := make([]Card, len(desc.Cards))
+	copy(v, desc.Cards)
+	desc.Cards = v
+	decks[desc.Type] = desc
+	return nil
+}
+
+// frenchCards returns the French cards having the rank or higher.
+func frenchCards(low Rank) []Card {
+	var v []Card
+	for _, s := range []Suit{Spade, Heart, Diamond, Club} {
+		for r := low; r <= Ace; r++ {
+			v = append(v, New(r, s))
+		}
+	}
+	return v
+}
+
 func init() {
+	for _, desc := range []DeckDesc{
+		{DeckFrench, "French", frenchCards(Two)},
+		{DeckShort, "Short", frenchCards(Six)},
+		{DeckManila, "Manila", frenchCards(Seven)},
+		{DeckRoyal, "Royal", frenchCards(Ten)},
+		{DeckJoker, "Joker", append(frenchCards(Two), BlackJoker)},
+		{DeckJokers, "Jokers", append(frenchCards(Two), BlackJoker, RedJoker)},
+	} {
+		if err := RegisterDeck(desc); err != nil {
+			panic(err)
+		}
+	}
 	unshuffledFrench = DeckFrench.Unshuffled()
 	unshuffledShort = DeckShort.Unshuffled()
 	unshuffledManila = DeckManila.Unshuffled()
@@ -99,9 +160,14 @@ func init() {
 
 // Deck is a set of playing cards.
 type Deck struct {
-	i int
-	l int
-	v []Card
+	i   int
+	l   int
+	v   []Card
+	r   []Card
+	t   []Card
+	cut int
+	out bool
+	f   func()
 }
 
 // NewDeck returns a new deck of 52 unshuffled cards.
@@ -130,6 +196,93 @@ func (d *Deck) SetLimit(limit int) {
 	d.l = limit
 }
 
+// SetCut sets the position of the deck's cut card. The cut card comes out once
+// pos cards have been drawn from the deck. A pos of 0 removes the cut card.
+//
+// Useful when using a card deck "shoe", to signal when the shoe should be
+// reshuffled. See CutCard, OnCut, and Reshuffle.
+func (d *Deck) SetCut(pos int) {
+	d.cut, d.out = pos, 0 < pos && pos <= d.i
+}
+
+// SetPenetration sets the deck's cut card at the penetration p (0 < p <= 1)
+// of the deck's cards. See SetCut.
+func (d *Deck) SetPenetration(p float64) {
+	d.SetCut(int(p * float64(d.l)))
+}
+
+// Cut returns the position of the deck's cut card, or 0 when the deck does not
+// have a cut card.
+func (d *Deck) Cut() int {
+	return d.cut
+}
+
+// CutCard returns true when the deck's cut card has come out.
+func (d *Deck) CutCard() bool {
+	return d.out
+}
+
+// OnCut sets a func to call when the deck's cut card comes out during a draw.
+func (d *Deck) OnCut(f func()) {
+	d.f = f
+}
+
+// Discard places previously drawn cards in the deck's discard tray. Returns
+// ErrInvalidCard when a card has not been drawn, or has already been
+// discarded. When an error is returned, no cards are discarded.
+func (d *Deck) Discard(cards ...Card) error {
+	m := make(map[Card]int)
+	for _, c := range d.v[:min(d.i, d.l)] {
+		m[c]++
+	}
+	for _, c := range d.t {
+		m[c]--
+	}
+	for _, c := range cards {
+		if m[c] <= 0 {
+			return ErrInvalidCard
+		}
+		m[c]--
+	}
+	d.t = append(d.t, cards...)
+	return nil
+}
+
+// Tray returns a copy of the cards in the deck's discard tray.
+func (d *Deck) Tray() []Card {
+	v := make([]Card, len(d.t))
+	copy(v, d.t)
+	return v
+}
+
+// Reshuffle returns the cards in the deck's discard tray to the deck, and
+// shuffles them with the deck's remaining cards using the provided shuffler.
+// Drawn cards that have not been discarded remain drawn. Empties the discard
+// tray and resets the cut card (see SetCut).
+func (d *Deck) Reshuffle(shuffler Shuffler) {
+	m := make(map[Card]int)
+	for _, c := range d.t {
+		m[c]++
+	}
+	i := min(d.i, d.l)
+	var v []Card
+	for _, c := range d.v[:i] {
+		if m[c] != 0 {
+			m[c]--
+			continue
+		}
+		v = append(v, c)
+	}
+	n := len(v)
+	v = append(append(v, d.t...), d.v[i:d.l]...)
+	shuffler.Shuffle(len(v)-n, func(i, j int) {
+		v[n+i], v[n+j] = v[n+j], v[n+i]
+	})
+	l := len(v)
+	d.v, d.i, d.l, d.t = append(v, d.v[d.l:]...), n, l, nil
+	d.out = 0 < d.cut && d.cut <= d.i
+}
+
 // Shuffle shuffles the deck's cards using the provided shuffler.
 func (d *Deck) Shuffle(shuffler Shuffler) {
 	shuffler.Shuffle(len(d.v), func(i, j int) {
@@ -155,6 +308,12 @@ func (d *Deck) Draw(n int) []Card {
 	for l := min(d.i+n, d.l); d.i < l; d.i++ {
 		hand = append(hand, d.v[d.i])
 	}
+	if 0 < d.cut && !d.out && d.cut <= d.i {
+		d.out = true
+		if d.f != nil {
+			d.f()
+		}
+	}
 	return hand
 }
 
@@ -178,9 +337,105 @@ func (d *Deck) All() []Card {
 	return v
 }
 
-// Reset resets the deck.
+// Remove removes the cards from the deck's remaining cards, preserving the
+// order of the remaining cards. Returns ErrDuplicateCard when a card has
+// already been removed, or ErrInvalidCard when a card is otherwise not
+// present in the deck's remaining cards. When an error is returned, no cards
+// are removed.
+//
+// Removed cards are not restored by Reset, and can be reinserted into the deck
+// with Insert.
+func (d *Deck) Remove(cards ...Card) error {
+	remaining, removed := make(map[Card]int), make(map[Card]int)
+	for _, c := range d.v[min(d.i, d.l):d.l] {
+		remaining[c]++
+	}
+	for _, c := range d.r {
+		removed[c]++
+	}
+	m := make(map[Card]int)
+	for _, c := range cards {
+		switch {
+		case m[c] < remaining[c]:
+			m[c]++
+		case removed[c]+m[c] != 0:
+			return ErrDuplicateCard
+		default:
+			return ErrInvalidCard
+		}
+	}
+	v := append([]Card(nil), d.v[:d.i]...)
+	for _, c := range d.v[min(d.i, d.l):d.l] {
+		if m[c] != 0 {
+			m[c]--
+			continue
+		}
+		v = append(v, c)
+	}
+	l := len(v)
+	d.v, d.l, d.r = append(v, d.v[d.l:]...), l, append(d.r, cards...)
+	return nil
+}
+
+// Insert inserts previously removed cards back into the deck, after the deck's
+// remaining cards. Returns ErrInvalidCard when a card has not been removed
+// from the deck. When an error is returned, no cards are inserted.
+func (d *Deck) Insert(cards ...Card) error {
+	removed := make(map[Card]int)
+	for _, c := range d.r {
+		removed[c]++
+	}
+	for _, c := range cards {
+		if removed[c] == 0 {
+			return ErrInvalidCard
+		}
+		removed[c]--
+	}
+	// drop from removed
+	m := make(map[Card]int)
+	for _, c := range cards {
+		m[c]++
+	}
+	var r []Card
+	for _, c := range d.r {
+		if m[c] != 0 {
+			m[c]--
+			continue
+		}
+		r = append(r, c)
+	}
+	d.insert(cards)
+	d.r = r
+	return nil
+}
+
+// insert inserts the cards after the deck's remaining cards.
+func (d *Deck) insert(cards []Card) {
+	v := make([]Card, 0, len(d.v)+len(cards))
+	v = append(append(append(v, d.v[:d.l]...), cards...), d.v[d.l:]...)
+	d.v, d.l = v, d.l+len(cards)
+}
+
+// Contains returns true when the card is in the deck's remaining cards.
+func (d *Deck) Contains(c Card) bool {
+	for _, card := range d.v[min(d.i, d.l):d.l] {
+		if card == c {
+			return true
+		}
+	}
+	return false
+}
+
+// Removed returns a copy of the cards removed from the deck.
+func (d *Deck) Removed() []Card {
+	v := make([]Card, len(d.r))
+	copy(v, d.r)
+	return v
+}
+
+// Reset resets the deck, emptying the deck's discard tray.
 func (d *Deck) Reset() {
-	d.i = 0
+	d.i, d.t, d.out = 0, nil, false
 }
 
 // Deal draws one card successively for each hand until each hand has n cards.
@@ -257,22 +512,40 @@ func (d *Deck) Badugi(hands int) ([][]Card, []Card) {
 // Dealer is a deck and street iterator.
 type Dealer struct {
 	TypeDesc
-	d *Deck
-	i int
+	d         *Deck
+	i         int
+	shuffler  Shuffler
+	burned    []Card
+	discards  map[int][]Card
+	streets   map[int]int
+	muck      []Card
+	draws     map[int][]int
+	up        map[int][]Card
+	community []Card
+	folds     map[int][]Card
 }
 
-// NewDealer creates a new dealer.
+// NewDealer creates a new dealer. Returns nil when the type description's
+// deck does not have enough cards to deal to Max hands (see
+// TypeDesc.CanDeal).
 func NewDealer(desc TypeDesc, shuffler Shuffler, n int) *Dealer {
+	if !desc.CanDeal(desc.Max) {
+		return nil
+	}
 	d := desc.Type.Deck()
 	d.ShuffleN(shuffler, n)
 	return &Dealer{
 		TypeDesc: desc,
 		d:        d,
 		i:        -1,
+		shuffler: shuffler,
 	}
 }
 
 // NewShuffledDealer creates a new dealer for an already shuffled deck.
+//
+// The dealer has no shuffler for reshuffling the muck when drawing (see
+// SetShuffler).
 func NewShuffledDealer(desc TypeDesc, d *Deck) *Dealer {
 	return &Dealer{
 		TypeDesc: desc,
@@ -302,6 +575,9 @@ func (d *Dealer) Format(f fmt.State, verb rune) {
 			}
 			v = append(v, fmt.Sprintf("b: %d", desc.Board))
 		}
+		if 0 < desc.Discard {
+			v = append(v, fmt.Sprintf("x: %d", desc.Discard))
+		}
 		var s string
 		if len(v) != 0 {
 			s = " (" + strings.Join(v, ", ") + ")"
@@ -336,23 +612,62 @@ func (d *Dealer) Board() int {
 	return d.Streets[d.i].Board
 }
 
-// Deal deals cards for the street.
+// Deal deals cards for the street. A community card dealt for the street (see
+// Community) is appended to the board.
 func (d *Dealer) Deal(pockets [][]Card, board []Card, hands int) ([][]Card, []Card) {
-	return d.DealPockets(pockets, hands, true), d.DealBoard(board, true)
+	n := len(d.community)
+	pockets = d.DealPockets(pockets, hands, true)
+	return pockets, d.DealBoard(append(board, d.community[n:]...), true)
 }
 
 // DealPockets deals and appends pockets, returning the appended slice.
+//
+// When the last street dealing pockets deals a single pocket card, and the
+// deck does not have enough remaining cards to deal a card to every hand, a
+// single community card shared by all hands is dealt instead (see Community).
+// Otherwise, no cards are dealt when the deck does not have enough remaining
+// cards for every hand. Folded players are not dealt cards (see Fold).
 func (d *Dealer) DealPockets(pockets [][]Card, hands int, discard bool) [][]Card {
 	if p := d.Streets[d.i].Pocket; 0 < p {
-		if n := d.Streets[d.i].PocketDiscard; discard && 0 < n {
-			_ = d.d.Draw(n)
+		n := d.Streets[d.i].PocketDiscard
+		if !discard {
+			n = 0
+		}
+		active := hands
+		for i := range d.folds {
+			if i < hands {
+				active--
+			}
+		}
+		switch remaining := d.d.Remaining(); {
+		case p*active+n <= remaining:
+		case p == 1 && lastPocket(d.Streets, d.i):
+			d.burned = append(d.burned, d.d.Draw(n)...)
+			d.community = append(d.community, d.d.Draw(1)...)
+			return pockets
+		default:
+			return pockets
+		}
+		if 0 < n {
+			d.burned = append(d.burned, d.d.Draw(n)...)
 		}
 		if pockets == nil {
 			pockets = make([][]Card, hands)
 		}
+		up := d.Streets[d.i].PocketUp
+		if 0 < up && d.up == nil {
+			d.up = make(map[int][]Card)
+		}
 		for j := 0; j < p; j++ {
 			for i := 0; i < hands; i++ {
-				pockets[i] = append(pockets[i], d.d.Draw(1)[0])
+				if d.Folded(i) {
+					continue
+				}
+				c := d.d.Draw(1)[0]
+				pockets[i] = append(pockets[i], c)
+				if p-up <= j {
+					d.up[i] = append(d.up[i], c)
+				}
 			}
 		}
 	}
@@ -363,27 +678,987 @@ func (d *Dealer) DealPockets(pockets [][]Card, hands int, discard bool) [][]Card
 func (d *Dealer) DealBoard(board []Card, discard bool) []Card {
 	if p := d.Streets[d.i].Board; 0 < p {
 		if n := d.Streets[d.i].BoardDiscard; discard && 0 < n {
-			_ = d.d.Draw(n)
+			d.burned = append(d.burned, d.d.Draw(n)...)
 		}
 		board = append(board, d.d.Draw(p)...)
 	}
 	return board
 }
 
+// Discard discards cards from the player's pocket at the end of the current
+// street, returning the remaining pocket cards. The number of discarded cards
+// must be the same as the street's Discard count, and a player can only
+// discard once per street. Discarded cards are not replaced, and are tracked
+// by the dealer (see Discarded).
+func (d *Dealer) Discard(player int, pocket []Card, cards ...Card) ([]Card, error) {
+	if d.i < 0 || len(d.Streets) <= d.i || d.Streets[d.i].Discard != len(cards) || len(cards) == 0 {
+		return nil, ErrInvalidDiscard
+	}
+	if i, ok := d.streets[player]; ok && i == d.i {
+		return nil, ErrInvalidDiscard
+	}
+	v := make([]Card, len(pocket))
+	copy(v, pocket)
+	for _, c := range cards {
+		i := 0
+		for ; i < len(v) && v[i] != c; i++ {
+		}
+		if i == len(v) {
+			return nil, ErrInvalidDiscard
+		}
+		v = append(v[:i], v[i+1:]...)
+	}
+	if d.discards == nil {
+		d.discards, d.streets = make(map[int][]Card), make(map[int]int)
+	}
+	d.discards[player] = append(d.discards[player], cards...)
+	d.streets[player] = d.i
+	return v, nil
+}
+
+// Up returns the player's face up pocket cards, in the order dealt. The last
+// PocketUp cards dealt to a player on a street are face up.
+func (d *Dealer) Up(player int) []Card {
+	v := make([]Card, len(d.up[player]))
+	copy(v, d.up[player])
+	return v
+}
+
+// BringIn returns the player that brings in, determined by the first face up
+// card of each player. For Razz and London, the player with the highest card
+// (Ace low) brings in, otherwise the player with the lowest card (Ace high).
+// Ties are broken by suit, with clubs the lowest, followed by diamonds, hearts,
+// and spades. Returns -1 when no face up cards have been dealt.
+func (d *Dealer) BringIn() int {
+	low := d.lowUp()
+	player, best := -1, 0
+	for i, up := range d.up {
+		if len(up) == 0 || d.Folded(i) {
+			continue
+		}
+		v := 4*upRank(up[0], low) + suitOrder(up[0].Suit())
+		if !low {
+			v = -v
+		}
+		if player == -1 || best < v || (best == v && i < player) {
+			player, best = i, v
+		}
+	}
+	return player
+}
+
+// FirstToAct returns the player that acts first on the current street. On the
+// first street, the player that brings in acts first (see BringIn). On later
+// streets, the remaining (not folded) player with the best face up cards acts
+// first, being the lowest hand for Razz and London, and the highest hand
+// otherwise. Straights and flushes are not considered, and ties are broken by
+// the lowest player. Returns -1 when no face up cards have been dealt.
+func (d *Dealer) FirstToAct() int {
+	if d.i <= 0 {
+		return d.BringIn()
+	}
+	low := d.lowUp()
+	player, best := -1, []int(nil)
+	for i, up := range d.up {
+		if len(up) == 0 || d.Folded(i) {
+			continue
+		}
+		v := upKey(up, low)
+		if player == -1 {
+			player, best = i, v
+			continue
+		}
+		switch n := compareKeys(v, best); {
+		case low && n < 0, !low && 0 < n, n == 0 && i < player:
+			player, best = i, v
+		}
+	}
+	return player
+}
+
+// lowUp returns true when face up cards are ranked low (ie, Razz and London).
+func (d *Dealer) lowUp() bool {
+	return d.Eval == EvalRazz || d.Eval == EvalAceSix
+}
+
+// upRank returns the rank value of the card, with Ace low when low is true.
+func upRank(c Card, low bool) int {
+	if low {
+		return c.AceIndex()
+	}
+	return int(c.Rank())
+}
+
+// suitOrder returns the suit order of the suit, from clubs (0) to spades (3).
+func suitOrder(suit Suit) int {
+	switch suit {
+	case Spade:
+		return 3
+	case Heart:
+		return 2
+	case Diamond:
+		return 1
+	}
+	return 0
+}
+
+// upKey returns the comparison key for face up cards, being the counts of
+// each rank (largest first), followed by the ranks ordered by count and rank
+// (highest first).
+func upKey(v []Card, low bool) []int {
+	counts := make(map[int]int)
+	var ranks []int
+	for _, c := range v {
+		r := upRank(c, low)
+		if counts[r] == 0 {
+			ranks = append(ranks, r)
+		}
+		counts[r]++
+	}
+	sort.Slice(ranks, func(i, j int) bool {
+		if counts[ranks[i]] != counts[ranks[j]] {
+			return counts[ranks[i]] > counts[ranks[j]]
+		}
+		return ranks[i] > ranks[j]
+	})
+	key := make([]int, 0, 2*len(ranks))
+	for _, r := range ranks {
+		key = append(key, counts[r])
+	}
+	return append(key, ranks...)
+}
+
+// compareKeys compares keys a and b lexicographically.
+func compareKeys(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+// Discarded returns the cards discarded by the player.
+func (d *Dealer) Discarded(player int) []Card {
+	v := make([]Card, len(d.discards[player]))
+	copy(v, d.discards[player])
+	return v
+}
+
+// Draw draws replacement cards for the cards the player discards from their
+// pocket on the current street, returning the pocket with the discarded cards
+// replaced in place.
+//
+// The number of discarded cards must be no more than the street's PocketDraw
+// count, and a player can only draw once per street. When the type's Once is
+// true, a player can only draw cards on one street (standing pat, ie,
+// discarding no cards, does not count as a draw). Discarded cards are added to
+// the muck (see Muck).
+//
+// When the deck does not have enough remaining cards, the muck (excluding the
+// cards being discarded) is shuffled using the dealer's shuffler and placed
+// after the remaining cards. Returns ErrNotEnoughCards when there are still
+// not enough cards, or when the dealer has no shuffler.
+func (d *Dealer) Draw(player int, pocket []Card, cards ...Card) ([]Card, error) {
+	if d.i < 0 || len(d.Streets) <= d.i || d.Streets[d.i].PocketDraw < len(cards) {
+		return nil, ErrInvalidDraw
+	}
+	draws := d.draws[player]
+	switch {
+	case draws != nil && draws[d.i] != -1:
+		return nil, ErrInvalidDraw
+	case d.Once && len(cards) != 0:
+		for _, n := range draws {
+			if 0 < n {
+				return nil, ErrInvalidDraw
+			}
+		}
+	}
+	// determine positions
+	pos := make([]int, len(cards))
+	used := make([]bool, len(pocket))
+	for i, c := range cards {
+		j := 0
+		for ; j < len(pocket) && (used[j] || pocket[j] != c); j++ {
+		}
+		if j == len(pocket) {
+			return nil, ErrInvalidDraw
+		}
+		pos[i], used[j] = j, true
+	}
+	// reshuffle muck
+	if d.d.Remaining() < len(cards) {
+		if d.shuffler == nil || d.d.Remaining()+len(d.muck) < len(cards) {
+			return nil, ErrNotEnoughCards
+		}
+		muck := &Deck{v: d.muck, l: len(d.muck)}
+		muck.Shuffle(d.shuffler)
+		d.d.insert(muck.v)
+		d.muck = nil
+	}
+	v := make([]Card, len(pocket))
+	copy(v, pocket)
+	for i, c := range d.d.Draw(len(cards)) {
+		v[pos[i]] = c
+	}
+	if draws == nil {
+		draws = make([]int, len(d.Streets))
+		for i := range draws {
+			draws[i] = -1
+		}
+		if d.draws == nil {
+			d.draws = make(map[int][]int)
+		}
+		d.draws[player] = draws
+	}
+	draws[d.i] = len(cards)
+	d.muck = append(d.muck, cards...)
+	return v, nil
+}
+
+// Draws returns the number of cards drawn by the player on each street, or -1
+// when the player has not drawn on the street.
+func (d *Dealer) Draws(player int) []int {
+	v := make([]int, len(d.Streets))
+	for i := range v {
+		v[i] = -1
+	}
+	copy(v, d.draws[player])
+	return v
+}
+
+// Muck returns the cards discarded by players when drawing, that have not
+// been reshuffled into the deck.
+func (d *Dealer) Muck() []Card {
+	v := make([]Card, len(d.muck))
+	copy(v, d.muck)
+	return v
+}
+
+// SetShuffler sets the shuffler used to reshuffle the muck when drawing.
+func (d *Dealer) SetShuffler(shuffler Shuffler) {
+	d.shuffler = shuffler
+}
+
+// Fold folds the player. For types that show folded cards (see
+// TypeDesc.Show), the player's pocket is revealed, otherwise only the player's
+// face up cards (see Up) are revealed. Folded players are not dealt further
+// cards, and are excluded from runouts and equity calculations. Returns
+// ErrInvalidFold when the player has already folded.
+func (d *Dealer) Fold(player int, pocket []Card) error {
+	if d.Folded(player) {
+		return ErrInvalidFold
+	}
+	v := d.Up(player)
+	if d.Show {
+		v = make([]Card, len(pocket))
+		copy(v, pocket)
+	}
+	if d.folds == nil {
+		d.folds = make(map[int][]Card)
+	}
+	d.folds[player] = v
+	return nil
+}
+
+// Folded returns true when the player has folded.
+func (d *Dealer) Folded(player int) bool {
+	_, ok := d.folds[player]
+	return ok
+}
+
+// Revealed returns the cards revealed by the player when folding.
+func (d *Dealer) Revealed(player int) []Card {
+	v := make([]Card, len(d.folds[player]))
+	copy(v, d.folds[player])
+	return v
+}
+
+// Dead returns the publicly known dead cards, being the cards revealed by
+// folded players, ordered by player.
+func (d *Dealer) Dead() []Card {
+	players := make([]int, 0, len(d.folds))
+	for player := range d.folds {
+		players = append(players, player)
+	}
+	sort.Ints(players)
+	var v []Card
+	for _, player := range players {
+		v = append(v, d.folds[player]...)
+	}
+	return v
+}
+
+// active returns the players that have not folded, and their pockets.
+func (d *Dealer) active(pockets [][]Card) ([]int, [][]Card) {
+	var players []int
+	var v [][]Card
+	for i, pocket := range pockets {
+		if !d.Folded(i) {
+			players, v = append(players, i), append(v, pocket)
+		}
+	}
+	return players, v
+}
+
+// Community returns the community card shared by all hands, dealt when the
+// deck does not have enough remaining cards for every hand. Community cards
+// are used as board cards when ranking hands (see Type.RankHands).
+func (d *Dealer) Community() []Card {
+	v := make([]Card, len(d.community))
+	copy(v, d.community)
+	return v
+}
+
+// Burned returns the cards burned (discarded from the deck) by the dealer.
+func (d *Dealer) Burned() []Card {
+	v := make([]Card, len(d.burned))
+	copy(v, d.burned)
+	return v
+}
+
 // Reset resets the iterator to i.
 func (d *Dealer) Reset() {
 	d.d.Reset()
 	d.i = -1
+	d.burned, d.discards, d.streets = nil, nil, nil
+	d.muck, d.draws, d.up, d.community = nil, nil, nil, nil
+	d.folds = nil
 }
 
 // DealAll deals all pockets, board for the hands. Resets the dealer and the
 // deck.
+//
+// For streets having a Discard count, each player discards the last dealt
+// pocket cards. Returns nil pockets and board when the deck does not have
+// enough cards to deal all streets to the hands (see TypeDesc.CanDeal).
 func (d *Dealer) DealAll(hands int) ([][]Card, []Card) {
+	if !d.CanDeal(hands) {
+		return nil, nil
+	}
 	d.Reset()
 	var pockets [][]Card
 	var board []Card
 	for d.Next() {
 		pockets, board = d.Deal(pockets, board, hands)
+		if n := d.Streets[d.i].Discard; 0 < n {
+			for i := 0; i < hands; i++ {
+				pockets[i], _ = d.Discard(i, pockets[i], pockets[i][len(pockets[i])-n:]...)
+			}
+		}
 	}
 	return pockets, board
 }
+
+// Runout is a dealt runout of the remaining streets.
+type Runout struct {
+	// Pockets are the completed pockets.
+	Pockets [][]Card
+	// Board is the completed board. For Double types, contains the first
+	// board followed by the second board.
+	Board []Card
+	// Win is the win for the completed pockets and board.
+	Win Win
+}
+
+// RunOut deals n independent runouts of the streets remaining after the
+// current street from the deck, without reusing cards, evaluating the
+// completed pockets and board of each runout. Returns the runouts and each
+// pocket's fractional share of the pot, with the pot split equally between
+// the runouts. For Double types, the board contains the cards of the first
+// board followed by the cards of the second board, each of equal length.
+// Folded players (see Fold) are not dealt cards, and are ordered last in each
+// runout's win.
+//
+// The face up and community cards dealt for the runouts are not tracked by
+// the dealer (see Up and Community), and the dealer is advanced past the last
+// street.
+//
+// Returns ErrInvalidRunouts when n is less than 1, ErrInvalidBoard when a
+// Double board has an odd length, ErrInvalidDiscard when a remaining street
+// has player discards, or ErrNotEnoughCards when the deck does not have
+// enough cards for all runouts.
+func (d *Dealer) RunOut(pockets [][]Card, board []Card, n int) ([]Runout, []float64, error) {
+	start := d.i + 1
+	switch {
+	case n < 1:
+		return nil, nil, ErrInvalidRunouts
+	case d.Double && len(board)%2 != 0:
+		return nil, nil, ErrInvalidBoard
+	}
+	for _, street := range d.Streets[min(start, len(d.Streets)):] {
+		if 0 < street.Discard {
+			return nil, nil, ErrInvalidDiscard
+		}
+	}
+	active, _ := d.active(pockets)
+	remaining := d.d.Remaining()
+	for i := 0; i < n; i++ {
+		if remaining = d.remaining(remaining, start, len(active)); remaining < 0 {
+			return nil, nil, ErrNotEnoughCards
+		}
+	}
+	b1, b2 := board, []Card(nil)
+	if d.Double {
+		b1, b2 = board[:len(board)/2], board[len(board)/2:]
+	}
+	up, community := d.up, d.community
+	runouts, shares := make([]Runout, n), make([]float64, len(pockets))
+	for i := 0; i < n; i++ {
+		d.up, d.community = nil, nil
+		v := make([][]Card, len(pockets))
+		for j, pocket := range pockets {
+			v[j] = append(make([]Card, 0, len(pocket)), pocket...)
+		}
+		h1 := append(make([]Card, 0, len(b1)), b1...)
+		h2 := append(make([]Card, 0, len(b2)), b2...)
+		for d.i = start; d.i < len(d.Streets); d.i++ {
+			v, h1 = d.Deal(v, h1, len(pockets))
+			if d.Double {
+				h2 = d.DealBoard(h2, false)
+			}
+		}
+		runouts[i] = Runout{
+			Pockets: v,
+			Board:   append(h1, h2...),
+			Win:     d.win(v, h1, h2),
+		}
+		for j, share := range runouts[i].Win.Shares() {
+			shares[j] += share / float64(n)
+		}
+	}
+	d.up, d.community = up, community
+	return runouts, shares, nil
+}
+
+// win returns the win for the pockets and boards, excluding folded players.
+// Folded players are ordered last.
+func (d *Dealer) win(pockets [][]Card, b1, b2 []Card) Win {
+	active, v := d.active(pockets)
+	hi := d.Type.RankHands(v, b1)
+	var lo []*Hand
+	if d.Double {
+		lo = d.Type.RankHands(v, b2)
+	}
+	win := NewWin(hi, lo, d.Low)
+	if len(active) != len(pockets) {
+		win.Hi = activeOrder(win.Hi, active, len(pockets))
+		if win.Lo != nil {
+			win.Lo = activeOrder(win.Lo, active, len(pockets))
+		}
+	}
+	return win
+}
+
+// activeOrder maps the order of active players to the order of n players,
+// with inactive players ordered last.
+func activeOrder(order, active []int, n int) []int {
+	v, m := make([]int, 0, n), make([]bool, n)
+	for _, i := range order {
+		v, m[active[i]] = append(v, active[i]), true
+	}
+	for i := 0; i < n; i++ {
+		if !m[i] {
+			v = append(v, i)
+		}
+	}
+	return v
+}
+
+// deckVersion is the deck and dealer encoding version.
+const deckVersion = 1
+
+// deckState is the JSON encoded state of a deck.
+type deckState struct {
+	Version int    `json:"version"`
+	Pos     int    `json:"pos"`
+	Limit   int    `json:"limit"`
+	Cards   []Card `json:"cards"`
+	Removed []Card `json:"removed,omitempty"`
+	Tray    []Card `json:"tray,omitempty"`
+	Cut     int    `json:"cut,omitempty"`
+	Out     bool   `json:"out,omitempty"`
+}
+
+// MarshalJSON satisfies the json.Marshaler interface.
+func (d *Deck) MarshalJSON() ([]byte, error) {
+	return json.Marshal(deckState{
+		Version: deckVersion,
+		Pos:     d.i,
+		Limit:   d.l,
+		Cards:   d.v,
+		Removed: d.r,
+		Tray:    d.t,
+		Cut:     d.cut,
+		Out:     d.out,
+	})
+}
+
+// UnmarshalJSON satisfies the json.Unmarshaler interface.
+func (d *Deck) UnmarshalJSON(buf []byte) error {
+	var st deckState
+	if err := json.Unmarshal(buf, &st); err != nil {
+		return err
+	}
+	if st.Version != deckVersion {
+		return ErrInvalidDeck
+	}
+	return d.set(st)
+}
+
+// MarshalBinary satisfies the encoding.BinaryMarshaler interface.
+//
+// The encoding is a version byte, followed by the position and limit as
+// uvarints, the cards, the removed cards, the discard tray, the cut card
+// position as a uvarint, and a byte when the cut card has come out. Cards are
+// encoded as a uvarint count followed by 2 bytes (rank, suit) per card. The
+// deck's OnCut func is not encoded.
+func (d *Deck) MarshalBinary() ([]byte, error) {
+	return d.appendBinary([]byte{deckVersion}), nil
+}
+
+// UnmarshalBinary satisfies the encoding.BinaryUnmarshaler interface.
+func (d *Deck) UnmarshalBinary(buf []byte) error {
+	r := &stateReader{buf: buf}
+	if r.byte() != deckVersion {
+		return ErrInvalidDeck
+	}
+	if err := d.readBinary(r); err != nil {
+		return err
+	}
+	if len(r.buf) != 0 {
+		return ErrInvalidDeck
+	}
+	return nil
+}
+
+// appendBinary appends the binary encoding of the deck (without version) to
+// buf.
+func (d *Deck) appendBinary(buf []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(d.i))
+	buf = binary.AppendUvarint(buf, uint64(d.l))
+	buf = appendCards(buf, d.v)
+	buf = appendCards(buf, d.r)
+	buf = appendCards(buf, d.t)
+	buf = binary.AppendUvarint(buf, uint64(d.cut))
+	if d.out {
+		return append(buf, 1)
+	}
+	return append(buf, 0)
+}
+
+// readBinary reads the binary encoding of the deck (without version) from r.
+func (d *Deck) readBinary(r *stateReader) error {
+	st := deckState{
+		Pos:   int(r.uvarint()),
+		Limit: int(r.uvarint()),
+	}
+	st.Cards, st.Removed, st.Tray = r.cards(), r.cards(), r.cards()
+	st.Cut = int(r.uvarint())
+	switch r.byte() {
+	case 0:
+	case 1:
+		st.Out = true
+	default:
+		r.err = true
+	}
+	if r.err {
+		return ErrInvalidDeck
+	}
+	return d.set(st)
+}
+
+// set validates and sets the deck's state.
+func (d *Deck) set(st deckState) error {
+	if st.Pos < 0 || len(st.Cards) < st.Pos || st.Limit < 0 || len(st.Cards) < st.Limit || st.Cut < 0 {
+		return ErrInvalidDeck
+	}
+	for _, v := range [][]Card{st.Cards, st.Removed, st.Tray} {
+		for _, c := range v {
+			if c == InvalidCard {
+				return ErrInvalidCard
+			}
+		}
+	}
+	// tray cards must have been drawn
+	m := make(map[Card]int)
+	for _, c := range st.Cards[:min(st.Pos, st.Limit)] {
+		m[c]++
+	}
+	for _, c := range st.Tray {
+		if m[c]--; m[c] < 0 {
+			return ErrInvalidDeck
+		}
+	}
+	d.i, d.l, d.v, d.r = st.Pos, st.Limit, st.Cards, st.Removed
+	d.t, d.cut, d.out = st.Tray, st.Cut, st.Out
+	if len(d.t) == 0 {
+		d.t = nil
+	}
+	if d.v == nil {
+		d.v = []Card{}
+	}
+	return nil
+}
+
+// dealerState is the JSON encoded state of a dealer.
+type dealerState struct {
+	Version   int           `json:"version"`
+	Type      Type          `json:"type"`
+	Street    int           `json:"street"`
+	Deck      *Deck         `json:"deck"`
+	Burned    []Card        `json:"burned,omitempty"`
+	Muck      []Card        `json:"muck,omitempty"`
+	Community []Card        `json:"community,omitempty"`
+	Players   []playerState `json:"players,omitempty"`
+}
+
+// playerState is the JSON encoded state of a dealer's player.
+type playerState struct {
+	Player   int    `json:"player"`
+	Street   int    `json:"street"`
+	Discards []Card `json:"discards,omitempty"`
+	Draws    []int  `json:"draws,omitempty"`
+	Up       []Card `json:"up,omitempty"`
+	Folded   bool   `json:"folded,omitempty"`
+	Revealed []Card `json:"revealed,omitempty"`
+}
+
+// MarshalJSON satisfies the json.Marshaler interface.
+func (d *Dealer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(dealerState{
+		Version:   deckVersion,
+		Type:      d.Type,
+		Street:    d.i,
+		Deck:      d.d,
+		Burned:    d.burned,
+		Muck:      d.muck,
+		Community: d.community,
+		Players:   d.players(),
+	})
+}
+
+// UnmarshalJSON satisfies the json.Unmarshaler interface.
+//
+// Only the dealer's type id is encoded, and the dealer's type description is
+// restored from the registered type. As such, only dealers of registered types
+// round trip: ErrInvalidType is returned for a dealer created with a type
+// description that was not registered (see RegisterType), and any changes made
+// to a registered type's description are not restored.
+//
+// The dealer's shuffler is not restored: set it with SetShuffler before
+// drawing. A dealer only resumes the same deal when given the same shuffler,
+// in the same state, as when marshaled (see FairShuffler.MarshalBinary).
+func (d *Dealer) UnmarshalJSON(buf []byte) error {
+	var st dealerState
+	if err := json.Unmarshal(buf, &st); err != nil {
+		return err
+	}
+	switch {
+	case st.Version != deckVersion:
+		return ErrInvalidDealer
+	case st.Deck == nil:
+		return ErrInvalidDeck
+	}
+	return d.set(st)
+}
+
+// MarshalBinary satisfies the encoding.BinaryMarshaler interface.
+//
+// The encoding is a version byte, followed by the type id (2 bytes), the
+// street as a varint, the deck (see Deck.MarshalBinary, without version), the
+// burned cards, the muck, the community cards, and the players as a uvarint
+// count followed by the player and discard street as varints, the player's
+// discarded cards, the player's draws as a uvarint count followed by varints,
+// the player's face up cards, a folded byte (0 or 1), and the player's
+// revealed cards.
+func (d *Dealer) MarshalBinary() ([]byte, error) {
+	buf := []byte{deckVersion}
+	buf = binary.BigEndian.AppendUint16(buf, uint16(d.Type))
+	buf = binary.AppendVarint(buf, int64(d.i))
+	buf = d.d.appendBinary(buf)
+	buf = appendCards(buf, d.burned)
+	buf = appendCards(buf, d.muck)
+	buf = appendCards(buf, d.community)
+	players := d.players()
+	buf = binary.AppendUvarint(buf, uint64(len(players)))
+	for _, p := range players {
+		buf = binary.AppendVarint(buf, int64(p.Player))
+		buf = binary.AppendVarint(buf, int64(p.Street))
+		buf = appendCards(buf, p.Discards)
+		buf = binary.AppendUvarint(buf, uint64(len(p.Draws)))
+		for _, n := range p.Draws {
+			buf = binary.AppendVarint(buf, int64(n))
+		}
+		buf = appendCards(buf, p.Up)
+		var folded byte
+		if p.Folded {
+			folded = 1
+		}
+		buf = appendCards(append(buf, folded), p.Revealed)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary satisfies the encoding.BinaryUnmarshaler interface.
+//
+// The dealer's type description and shuffler are restored the same as with
+// UnmarshalJSON.
+func (d *Dealer) UnmarshalBinary(buf []byte) error {
+	r := &stateReader{buf: buf}
+	if r.byte() != deckVersion {
+		return ErrInvalidDealer
+	}
+	st := dealerState{
+		Type:   Type(r.byte())<<8 | Type(r.byte()),
+		Street: int(r.varint()),
+		Deck:   new(Deck),
+	}
+	if err := st.Deck.readBinary(r); err != nil {
+		return err
+	}
+	st.Burned, st.Muck, st.Community = r.cards(), r.cards(), r.cards()
+	n := r.uvarint()
+	for i := uint64(0); i < n && !r.err; i++ {
+		p := playerState{
+			Player:   int(r.varint()),
+			Street:   int(r.varint()),
+			Discards: r.cards(),
+		}
+		m := r.uvarint()
+		for j := uint64(0); j < m && !r.err; j++ {
+			p.Draws = append(p.Draws, int(r.varint()))
+		}
+		p.Up = r.cards()
+		switch r.byte() {
+		case 0:
+		case 1:
+			p.Folded = true
+		default:
+			r.err = true
+		}
+		p.Revealed = r.cards()
+		st.Players = append(st.Players, p)
+	}
+	if r.err || len(r.buf) != 0 {
+		return ErrInvalidDealer
+	}
+	return d.set(st)
+}
+
+// players returns the dealer's player states, ordered by player.
+func (d *Dealer) players() []playerState {
+	m := make(map[int]bool)
+	for player := range d.discards {
+		m[player] = true
+	}
+	for player := range d.draws {
+		m[player] = true
+	}
+	for player := range d.up {
+		m[player] = true
+	}
+	for player := range d.folds {
+		m[player] = true
+	}
+	var v []playerState
+	for player := range m {
+		v = append(v, playerState{
+			Player:   player,
+			Street:   d.streets[player],
+			Discards: d.discards[player],
+			Draws:    d.draws[player],
+			Up:       d.up[player],
+			Folded:   d.Folded(player),
+			Revealed: d.folds[player],
+		})
+	}
+	sort.Slice(v, func(i, j int) bool {
+		return v[i].Player < v[j].Player
+	})
+	return v
+}
+
+// set validates and sets the dealer's state.
+func (d *Dealer) set(st dealerState) error {
+	desc, ok := descs[st.Type]
+	if !ok {
+		return ErrInvalidType
+	}
+	if st.Street < -1 || len(desc.Streets) < st.Street {
+		return ErrInvalidDealer
+	}
+	for _, v := range [][]Card{st.Burned, st.Muck, st.Community} {
+		for _, c := range v {
+			if c == InvalidCard {
+				return ErrInvalidCard
+			}
+		}
+	}
+	var discards map[int][]Card
+	var streets map[int]int
+	var draws map[int][]int
+	var up, folds map[int][]Card
+	seen := make(map[int]bool)
+	for _, p := range st.Players {
+		if seen[p.Player] {
+			return ErrInvalidDealer
+		}
+		seen[p.Player] = true
+		if len(p.Discards) != 0 {
+			if p.Street < 0 || len(desc.Streets) <= p.Street {
+				return ErrInvalidDealer
+			}
+			for _, c := range p.Discards {
+				if c == InvalidCard {
+					return ErrInvalidCard
+				}
+			}
+			if discards == nil {
+				discards, streets = make(map[int][]Card), make(map[int]int)
+			}
+			discards[p.Player], streets[p.Player] = p.Discards, p.Street
+		}
+		if len(p.Draws) != 0 {
+			if len(p.Draws) != len(desc.Streets) {
+				return ErrInvalidDealer
+			}
+			for i, n := range p.Draws {
+				if n < -1 || desc.Streets[i].PocketDraw < n {
+					return ErrInvalidDealer
+				}
+			}
+			if draws == nil {
+				draws = make(map[int][]int)
+			}
+			draws[p.Player] = p.Draws
+		}
+		if len(p.Up) != 0 {
+			for _, c := range p.Up {
+				if c == InvalidCard {
+					return ErrInvalidCard
+				}
+			}
+			if up == nil {
+				up = make(map[int][]Card)
+			}
+			up[p.Player] = p.Up
+		}
+		for _, c := range p.Revealed {
+			if c == InvalidCard {
+				return ErrInvalidCard
+			}
+		}
+		switch {
+		case p.Folded && folds == nil:
+			folds = make(map[int][]Card)
+			fallthrough
+		case p.Folded:
+			folds[p.Player] = p.Revealed
+		case len(p.Revealed) != 0:
+			return ErrInvalidDealer
+		}
+	}
+	*d = Dealer{
+		TypeDesc:  desc,
+		d:         st.Deck,
+		i:         st.Street,
+		burned:    st.Burned,
+		discards:  discards,
+		streets:   streets,
+		muck:      st.Muck,
+		draws:     draws,
+		up:        up,
+		community: st.Community,
+		folds:     folds,
+	}
+	return nil
+}
+
+// appendCards appends the cards to buf as a uvarint count followed by the
+// rank and suit bytes of each card.
+func appendCards(buf []byte, v []Card) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(v)))
+	for _, c := range v {
+		buf = append(buf, c.RankByte(), c.SuitByte())
+	}
+	return buf
+}
+
+// appendBytes appends the binary encoding of v, as a uvarint length followed
+// by the bytes, to buf.
+func appendBytes(buf []byte, v []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(v)))
+	return append(buf, v...)
+}
+
+// stateReader reads binary encoded deck and dealer state.
+type stateReader struct {
+	buf []byte
+	err bool
+}
+
+// byte reads a byte.
+func (r *stateReader) byte() byte {
+	if len(r.buf) == 0 {
+		r.err = true
+		return 0
+	}
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b
+}
+
+// uvarint reads a uvarint.
+func (r *stateReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.err = true
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+// varint reads a varint.
+func (r *stateReader) varint() int64 {
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.err = true
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+// cards reads cards encoded by appendCards. Invalid cards are read as
+// InvalidCard.
+func (r *stateReader) cards() []Card {
+	n := r.uvarint()
+	if r.err || uint64(len(r.buf)/2) < n {
+		r.err = true
+		return nil
+	}
+	v := make([]Card, n)
+	for i := range v {
+		v[i] = FromString(string(r.buf[2*i : 2*i+2]))
+	}
+	r.buf = r.buf[2*n:]
+	return v
+}
+
+// bytes reads bytes encoded by appendBytes.
+func (r *stateReader) bytes() []byte {
+	n := r.uvarint()
+	if r.err || uint64(len(r.buf)) < n {
+		r.err = true
+		return nil
+	}
+	v := make([]byte, n)
+	copy(v, r.buf)
+	r.buf = r.buf[n:]
+	return v
+}
@@ -1,7 +1,10 @@
 package cardrank
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"math/rand"
 	"reflect"
 	"strconv"
@@ -86,6 +89,67 @@ func TestDeck(t *testing.T) {
 	}
 }
 
+func TestRegisterDeck(t *testing.T) {
+	// 40 card deck without 8, 9, and 10
+	var cards []Card
+	for _, s := range []Suit{Spade, Heart, Diamond, Club} {
+		for _, r := range []Rank{Two, Three, Four, Five, Six, Seven, Jack, Queen, King, Ace} {
+			cards = append(cards, New(r, s))
+		}
+	}
+	const deck, typ = DeckType(0x80), Type('Z'<<8 | 'f')
+	if err := RegisterDeck(DeckDesc{Type: deck, Name: "Forty", Cards: cards}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer delete(decks, deck)
+	for i, test := range []struct {
+		desc DeckDesc
+		err  error
+	}{
+		{DeckDesc{Type: deck, Cards: cards}, ErrInvalidId},
+		{DeckDesc{Type: DeckFrench, Cards: cards}, ErrInvalidId},
+		{DeckDesc{Type: deck + 1}, ErrInvalidDeck},
+		{DeckDesc{Type: deck + 1, Cards: []Card{InvalidCard}}, ErrInvalidCard},
+		{DeckDesc{Type: deck + 1, Cards: []Card{New(Ace, Spade) | 0x0f00}}, ErrInvalidCard},
+	} {
+		if err := RegisterDeck(test.desc); !errors.Is(err, test.err) {
+			t.Errorf("test %d expected error %v, got: %v", i, test.err, err)
+		}
+	}
+	if s, exp := deck.String(), "Forty"; s != exp {
+		t.Errorf("expected %q, got: %q", exp, s)
+	}
+	if v := deck.Unshuffled(); !reflect.DeepEqual(v, cards) {
+		t.Errorf("expected %v, got: %v", cards, v)
+	}
+	desc, err := NewTypeDesc("Zf", typ, "Forty", WithHoldem())
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	desc.Deck = deck + 1
+	if err := RegisterType(*desc); !errors.Is(err, ErrInvalidDeck) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidDeck, err)
+	}
+	desc.Deck = deck
+	if err := RegisterType(*desc); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer delete(descs, typ)
+	defer delete(evals, typ)
+	if typ.DeckType() != deck {
+		t.Errorf("expected %s, got: %s", deck, typ.DeckType())
+	}
+	d := typ.Dealer(rand.New(rand.NewSource(0)), 1)
+	if n, exp := len(d.All()), 40; n != exp {
+		t.Fatalf("expected %d cards, got: %d", exp, n)
+	}
+	for _, c := range d.All() {
+		if r := c.Rank(); r == Eight || r == Nine || r == Ten {
+			t.Errorf("expected no %s", c)
+		}
+	}
+}
+
 func TestNewDeckShoe(t *testing.T) {
 	const decks = 7
 	d := NewShoeDeck(decks)
@@ -117,6 +181,82 @@ func TestNewDeckShoe(t *testing.T) {
 	}
 }
 
+func TestDeckShoeCut(t *testing.T) {
+	const decks = 6
+	d := NewShoeDeck(decks)
+	r := rand.New(rand.NewSource(0))
+	d.Shuffle(r)
+	d.SetPenetration(0.75)
+	if n, exp := d.Cut(), 234; n != exp {
+		t.Fatalf("expected cut %d, got: %d", exp, n)
+	}
+	var count int
+	d.OnCut(func() {
+		count++
+	})
+	var drawn int
+	for !d.CutCard() {
+		v := d.Draw(13)
+		drawn += len(v)
+		if err := d.Discard(v...); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	}
+	if drawn < 234 || 234+13 <= drawn {
+		t.Errorf("expected cut card to come out after 234 cards, drawn: %d", drawn)
+	}
+	pocket := d.Draw(2)
+	if count != 1 {
+		t.Errorf("expected OnCut to be called once, got: %d", count)
+	}
+	if pocket[0] != pocket[1] {
+		if err := d.Discard(pocket[0], pocket[0]); !errors.Is(err, ErrInvalidCard) {
+			t.Errorf("expected error %v, got: %v", ErrInvalidCard, err)
+		}
+	}
+	if n, exp := len(d.Tray()), drawn; n != exp {
+		t.Errorf("expected %d tray cards, got: %d", exp, n)
+	}
+	// round trip
+	buf, err := d.MarshalBinary()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	e := new(Deck)
+	if err := e.UnmarshalBinary(buf); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !e.CutCard() || e.Cut() != d.Cut() || !reflect.DeepEqual(e.Tray(), d.Tray()) {
+		t.Errorf("expected cut %d and %d tray cards, got: %d %d", d.Cut(), len(d.Tray()), e.Cut(), len(e.Tray()))
+	}
+	// reshuffle, leaving the pocket drawn
+	d.Reshuffle(r)
+	if d.CutCard() || len(d.Tray()) != 0 {
+		t.Errorf("expected cut card to be reset and tray to be empty")
+	}
+	if n, exp := d.Remaining(), decks*unshuffledSize-2; n != exp {
+		t.Errorf("expected %d remaining, got: %d", exp, n)
+	}
+	if err := d.Discard(pocket...); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	m := make(map[Card]int)
+	for _, c := range append(d.Draw(d.Remaining()), d.Tray()...) {
+		m[c]++
+	}
+	for _, c := range unshuffledFrench {
+		if m[c] != decks {
+			t.Errorf("expected %d %s, got: %d", decks, c, m[c])
+		}
+	}
+	if count != 2 {
+		t.Errorf("expected OnCut to be called twice, got: %d", count)
+	}
+	if err := NewDeck().Discard(New(Ace, Spade)); !errors.Is(err, ErrInvalidCard) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidCard, err)
+	}
+}
+
 func TestDeckDraw(t *testing.T) {
 	for exp := 1; exp < unshuffledSize; exp++ {
 		d := NewDeck()
@@ -174,6 +314,74 @@ func TestDeckDrawEmpty(t *testing.T) {
 	}
 }
 
+func TestDeckRemove(t *testing.T) {
+	d, err := DeckFrench.Without(Must("Ah Kh")...)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if n, exp := d.Remaining(), unshuffledSize-2; n != exp {
+		t.Fatalf("expected %d remaining, got: %d", exp, n)
+	}
+	for _, c := range Must("Ah Kh") {
+		if d.Contains(c) {
+			t.Errorf("expected deck to not contain %s", c)
+		}
+	}
+	if !d.Contains(Must("As")[0]) {
+		t.Errorf("expected deck to contain As")
+	}
+	tests := []struct {
+		cards string
+		err   error
+	}{
+		{"Ah", ErrDuplicateCard},
+		{"Qh Qh", ErrDuplicateCard},
+		{"Qh Ah", ErrDuplicateCard},
+		{"Qh Jh Kh", ErrDuplicateCard},
+	}
+	for i, test := range tests {
+		if err := d.Remove(Must(test.cards)...); !errors.Is(err, test.err) {
+			t.Errorf("test %d expected error %v, got: %v", i, test.err, err)
+		}
+		if n, exp := d.Remaining(), unshuffledSize-2; n != exp {
+			t.Errorf("test %d expected %d remaining, got: %d", i, exp, n)
+		}
+	}
+	short := DeckShort.New()
+	if err := short.Remove(Must("2h")...); !errors.Is(err, ErrInvalidCard) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidCard, err)
+	}
+	// drawn cards are not present
+	v := d.Draw(1)
+	if err := d.Remove(v...); !errors.Is(err, ErrInvalidCard) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidCard, err)
+	}
+	d.Reset()
+	if err := d.Insert(Must("As")...); !errors.Is(err, ErrInvalidCard) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidCard, err)
+	}
+	if err := d.Insert(Must("Kh")...); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !d.Contains(Must("Kh")[0]) {
+		t.Errorf("expected deck to contain Kh")
+	}
+	if v, exp := d.Removed(), Must("Ah"); !reflect.DeepEqual(v, exp) {
+		t.Errorf("expected removed %v, got: %v", exp, v)
+	}
+	if n, exp := d.Remaining(), unshuffledSize-1; n != exp {
+		t.Errorf("expected %d remaining, got: %d", exp, n)
+	}
+	// shoe decks remove a single instance
+	shoe := NewShoeDeck(2)
+	if err := shoe.Remove(Must("Ah Ah")...); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := shoe.Remove(Must("Ah")...); !errors.Is(err, ErrDuplicateCard) {
+		t.Errorf("expected error %v, got: %v", ErrDuplicateCard, err)
+	}
+}
+
 func TestDealer(t *testing.T) {
 	// seed := time.Now().UnixNano()
 	seed := int64(1676122011905868217)
@@ -189,6 +397,63 @@ func TestDealer(t *testing.T) {
 	}
 }
 
+func TestDealerDiscard(t *testing.T) {
+	d := Irish.Dealer(rand.New(rand.NewSource(0)), 1)
+	d.Next()
+	pockets, _ := d.Deal(nil, nil, 2)
+	if _, err := d.Discard(0, pockets[0], pockets[0][0]); !errors.Is(err, ErrInvalidDiscard) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidDiscard, err)
+	}
+	d.Next()
+	pockets, board := d.Deal(pockets, nil, 2)
+	for i, cards := range [][]Card{
+		pockets[0][:1],
+		pockets[0][:3],
+		{pockets[0][0], pockets[0][0]},
+		{pockets[0][0], pockets[1][0]},
+	} {
+		if _, err := d.Discard(0, pockets[0], cards...); !errors.Is(err, ErrInvalidDiscard) {
+			t.Errorf("test %d expected error %v, got: %v", i, ErrInvalidDiscard, err)
+		}
+	}
+	pocket, err := d.Discard(0, pockets[0], pockets[0][1], pockets[0][3])
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if exp := []Card{pockets[0][0], pockets[0][2]}; !reflect.DeepEqual(pocket, exp) {
+		t.Errorf("expected %v, got: %v", exp, pocket)
+	}
+	if exp, v := []Card{pockets[0][1], pockets[0][3]}, d.Discarded(0); !reflect.DeepEqual(v, exp) {
+		t.Errorf("expected %v, got: %v", exp, v)
+	}
+	if _, err := d.Discard(0, pocket, pocket...); !errors.Is(err, ErrInvalidDiscard) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidDiscard, err)
+	}
+	if _, err := Irish.Equity(rand.New(rand.NewSource(0)), 10, [][]Card{pocket, pockets[1][:2]}, board, d.Discarded(0)); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+	// deal all
+	for _, typ := range []Type{Pineapple, CrazyPineapple, LazyPineapple, Irish} {
+		d := typ.Dealer(rand.New(rand.NewSource(0)), 1)
+		pockets, board := d.DealAll(4)
+		m := make(map[Card]bool)
+		for i, pocket := range pockets {
+			if len(pocket) != 2 {
+				t.Errorf("%s expected pocket %d to have 2 cards, got: %v", typ, i, pocket)
+			}
+			for _, c := range append(pocket, d.Discarded(i)...) {
+				m[c] = true
+			}
+		}
+		for _, c := range board {
+			m[c] = true
+		}
+		if exp := 4*typ.Streets()[0].Pocket + 5; len(m) != exp {
+			t.Errorf("%s expected %d unique cards, got: %d", typ, exp, len(m))
+		}
+	}
+}
+
 func testDealer(t *testing.T, hands int, typ Type, seed int64) {
 	d := typ.Dealer(rand.New(rand.NewSource(seed)), 3)
 	t.Logf("Deck (%s, %d):", typ.DeckType(), len(d.d.v))
@@ -213,6 +478,15 @@ func testDealer(t *testing.T, hands int, typ Type, seed int64) {
 				t.Logf("         %v", b2)
 			}
 		}
+		if n := d.Street().Discard; 0 < n {
+			for i := 0; i < hands; i++ {
+				var err error
+				if pockets[i], err = d.Discard(i, pockets[i], pockets[i][:n]...); err != nil {
+					t.Fatalf("expected no error, got: %v", err)
+				}
+				t.Logf("  % 2d: %v discarded %v", i, pockets[i], d.Discarded(i))
+			}
+		}
 	}
 	h1 := typ.RankHands(pockets, b1)
 	var h2 []*Hand
@@ -242,3 +516,632 @@ func testDealer(t *testing.T, hands int, typ Type, seed int64) {
 }
 
 const unshuffledSize = 52
+
+func TestDealerMarshal(t *testing.T) {
+	for _, typ := range []Type{Holdem, Pineapple, Stud, Badugi} {
+		for _, enc := range []string{"json", "binary"} {
+			d := typ.Dealer(rand.New(rand.NewSource(1)), 1)
+			hands := 3
+			var pockets [][]Card
+			var board []Card
+			for i := 0; i < 2 && d.Next(); i++ {
+				pockets, board = testDealerStreet(t, d, pockets, board, hands)
+			}
+			var buf []byte
+			var err error
+			if enc == "json" {
+				buf, err = json.Marshal(d)
+			} else {
+				buf, err = d.MarshalBinary()
+			}
+			if err != nil {
+				t.Fatalf("%s %s expected no error, got: %v", typ, enc, err)
+			}
+			r := new(Dealer)
+			if enc == "json" {
+				err = json.Unmarshal(buf, r)
+			} else {
+				err = r.UnmarshalBinary(buf)
+			}
+			if err != nil {
+				t.Fatalf("%s %s expected no error, got: %v", typ, enc, err)
+			}
+			if r.Type != typ || r.i != d.i {
+				t.Errorf("%s %s expected type %s street %d, got: %s %d", typ, enc, typ, d.i, r.Type, r.i)
+			}
+			for i := 0; i < hands; i++ {
+				if v, exp := r.Discarded(i), d.Discarded(i); !reflect.DeepEqual(v, exp) {
+					t.Errorf("%s %s expected discarded %v, got: %v", typ, enc, exp, v)
+				}
+				if v, exp := r.Up(i), d.Up(i); !reflect.DeepEqual(v, exp) {
+					t.Errorf("%s %s expected up %v, got: %v", typ, enc, exp, v)
+				}
+			}
+			if v, exp := r.Burned(), d.Burned(); !reflect.DeepEqual(v, exp) {
+				t.Errorf("%s %s expected burned %v, got: %v", typ, enc, exp, v)
+			}
+			// continue dealing both
+			expPockets, expBoard := clonePockets(pockets), board
+			rPockets, rBoard := clonePockets(pockets), board
+			for d.Next() {
+				expPockets, expBoard = testDealerStreet(t, d, expPockets, expBoard, hands)
+			}
+			for r.Next() {
+				rPockets, rBoard = testDealerStreet(t, r, rPockets, rBoard, hands)
+			}
+			if !reflect.DeepEqual(rPockets, expPockets) || !reflect.DeepEqual(rBoard, expBoard) {
+				t.Errorf("%s %s expected %v %v, got: %v %v", typ, enc, expPockets, expBoard, rPockets, rBoard)
+			}
+			if v, exp := r.All(), d.All(); !reflect.DeepEqual(v, exp) {
+				t.Errorf("%s %s expected %v, got: %v", typ, enc, exp, v)
+			}
+		}
+	}
+}
+
+func TestDealerMarshalErrors(t *testing.T) {
+	d := Holdem.Dealer(rand.New(rand.NewSource(1)), 1)
+	d.Next()
+	buf, err := d.MarshalBinary()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := new(Dealer).UnmarshalBinary(buf[:len(buf)-1]); !errors.Is(err, ErrInvalidDealer) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidDealer, err)
+	}
+	tests := []struct {
+		s   string
+		err error
+	}{
+		{`{"version":1,"type":"Holdem","street":0,"deck":{"version":1,"pos":0,"limit":1,"cards":["Ah"]}}`, nil},
+		{`{"version":1,"type":"Holdem","street":0}`, ErrInvalidDeck},
+		{`{"version":1,"type":"Holdem","street":9,"deck":{"version":1,"pos":0,"limit":1,"cards":["Ah"]}}`, ErrInvalidDealer},
+		{`{"version":1,"type":"Holdem","street":0,"deck":{"version":1,"pos":2,"limit":1,"cards":["Ah"]}}`, ErrInvalidDeck},
+		{`{"version":1,"type":"Holdem","street":0,"deck":{"version":1,"pos":0,"limit":1,"cards":["Zz"]}}`, ErrInvalidCard},
+		{`{"version":1,"type":"Holdem","street":0,"deck":{"version":1,"pos":0,"limit":1,"cards":["Ah"]},"players":[{"player":0,"street":7,"discards":["Kh"]}]}`, ErrInvalidDealer},
+		{`{"type":"Holdem","street":0,"deck":{"version":1,"pos":0,"limit":1,"cards":["Ah"]}}`, ErrInvalidDealer},
+		{`{"version":1,"type":"Holdem","street":0,"deck":{"version":2,"pos":0,"limit":1,"cards":["Ah"]}}`, ErrInvalidDeck},
+	}
+	for i, test := range tests {
+		if err := json.Unmarshal([]byte(test.s), new(Dealer)); !errors.Is(err, test.err) {
+			t.Errorf("test %d expected error %v, got: %v", i, test.err, err)
+		}
+	}
+	// unregistered types do not round trip
+	desc := descs[Holdem]
+	desc.Type = 'X'<<8 | 'u'
+	d = NewDealer(desc, rand.New(rand.NewSource(1)), 1)
+	d.Next()
+	if buf, err = json.Marshal(d); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := json.Unmarshal(buf, new(Dealer)); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidType, err)
+	}
+	if buf, err = d.MarshalBinary(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := new(Dealer).UnmarshalBinary(buf); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidType, err)
+	}
+}
+
+func TestDeckMarshal(t *testing.T) {
+	d := NewShoeDeck(2)
+	d.SetLimit(80)
+	d.Shuffle(rand.New(rand.NewSource(2)))
+	if err := d.Remove(d.v[10], d.v[11]); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	d.Draw(7)
+	buf, err := d.MarshalBinary()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	b := new(Deck)
+	if err := b.UnmarshalBinary(buf); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !reflect.DeepEqual(b, d) {
+		t.Errorf("expected %v, got: %v", d, b)
+	}
+	if buf, err = json.Marshal(d); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	c := new(Deck)
+	if err := json.Unmarshal(buf, c); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !reflect.DeepEqual(c, d) {
+		t.Errorf("expected %v, got: %v", d, c)
+	}
+	if v, exp := c.Draw(100), d.Draw(100); !reflect.DeepEqual(v, exp) {
+		t.Errorf("expected %v, got: %v", exp, v)
+	}
+}
+
+// testDealerStreet deals the dealer's current street, discarding the first
+// pocket cards on streets with a Discard count.
+func testDealerStreet(t *testing.T, d *Dealer, pockets [][]Card, board []Card, hands int) ([][]Card, []Card) {
+	t.Helper()
+	pockets, board = d.Deal(pockets, board, hands)
+	if n := d.Street().Discard; 0 < n {
+		for i := 0; i < hands; i++ {
+			var err error
+			if pockets[i], err = d.Discard(i, pockets[i], pockets[i][:n]...); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+		}
+	}
+	return pockets, board
+}
+
+func clonePockets(pockets [][]Card) [][]Card {
+	v := make([][]Card, len(pockets))
+	for i, pocket := range pockets {
+		v[i] = append([]Card(nil), pocket...)
+	}
+	return v
+}
+
+func TestDealerDraw(t *testing.T) {
+	d := Badugi.Dealer(rand.New(rand.NewSource(3)), 1)
+	pocket := []Card(nil)
+	if _, err := d.Draw(0, pocket); !errors.Is(err, ErrInvalidDraw) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidDraw, err)
+	}
+	hands := 8
+	d.Next()
+	pockets, _ := d.Deal(nil, nil, hands)
+	if _, err := d.Draw(0, pockets[0], pockets[0][0]); !errors.Is(err, ErrInvalidDraw) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidDraw, err)
+	}
+	d.Next()
+	tests := []struct {
+		cards []Card
+		err   error
+	}{
+		{append(pockets[0], pockets[0][0]), ErrInvalidDraw},
+		{[]Card{pockets[0][0], pockets[0][0]}, ErrInvalidDraw},
+		{[]Card{pockets[1][0]}, ErrInvalidDraw},
+	}
+	for i, test := range tests {
+		if _, err := d.Draw(0, pockets[0], test.cards...); !errors.Is(err, test.err) {
+			t.Errorf("test %d expected error %v, got: %v", i, test.err, err)
+		}
+	}
+	// draw all cards each street, forcing a reshuffle of the muck
+	for street := 1; street < len(d.Streets); street++ {
+		if street != 1 {
+			d.Next()
+		}
+		for i := 0; i < hands; i++ {
+			v, err := d.Draw(i, pockets[i], pockets[i]...)
+			if err != nil {
+				t.Fatalf("street %d player %d expected no error, got: %v", street, i, err)
+			}
+			for j, c := range v {
+				if contains(pockets[i], c) {
+					t.Errorf("street %d player %d expected %s to be replaced", street, i, pockets[i][j])
+				}
+			}
+			pockets[i] = v
+		}
+		if _, err := d.Draw(0, pockets[0], pockets[0][0]); !errors.Is(err, ErrInvalidDraw) {
+			t.Errorf("street %d expected error %v, got: %v", street, ErrInvalidDraw, err)
+		}
+		// every card is in a pocket, the muck, or the deck
+		m := make(map[Card]int)
+		for _, pocket := range pockets {
+			for _, c := range pocket {
+				m[c]++
+			}
+		}
+		for _, c := range append(d.Muck(), d.d.v[d.d.i:d.d.l]...) {
+			m[c]++
+		}
+		if len(m) != unshuffledSize {
+			t.Errorf("street %d expected %d cards, got: %d", street, unshuffledSize, len(m))
+		}
+		for c, n := range m {
+			if n != 1 {
+				t.Errorf("street %d expected %s once, got: %d", street, c, n)
+			}
+		}
+	}
+	if v, exp := d.Draws(0), []int{-1, 4, 4, 4}; !reflect.DeepEqual(v, exp) {
+		t.Errorf("expected %v, got: %v", exp, v)
+	}
+	// not enough cards without a shuffler
+	s := NewShuffledDealer(d.TypeDesc, d.d)
+	s.i = 1
+	s.d.i = s.d.l
+	if _, err := s.Draw(0, pockets[0], pockets[0][0]); !errors.Is(err, ErrNotEnoughCards) {
+		t.Errorf("expected error %v, got: %v", ErrNotEnoughCards, err)
+	}
+}
+
+func TestDealerDrawOnce(t *testing.T) {
+	d := Lowball.Dealer(rand.New(rand.NewSource(4)), 1)
+	d.Next()
+	pockets, _ := d.Deal(nil, nil, 2)
+	d.Next()
+	// player 0 stands pat, player 1 draws
+	v, err := d.Draw(0, pockets[0])
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !reflect.DeepEqual(v, pockets[0]) {
+		t.Errorf("expected %v, got: %v", pockets[0], v)
+	}
+	if pockets[1], err = d.Draw(1, pockets[1], pockets[1][:2]...); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	d.Next()
+	if _, err := d.Draw(0, pockets[0], pockets[0][:1]...); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+	if _, err := d.Draw(1, pockets[1], pockets[1][:1]...); !errors.Is(err, ErrInvalidDraw) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidDraw, err)
+	}
+	// restored dealers keep draws
+	buf, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	r := new(Dealer)
+	if err := json.Unmarshal(buf, r); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if v, exp := r.Draws(i), d.Draws(i); !reflect.DeepEqual(v, exp) {
+			t.Errorf("player %d expected %v, got: %v", i, exp, v)
+		}
+	}
+	if v, exp := r.Muck(), d.Muck(); !reflect.DeepEqual(v, exp) {
+		t.Errorf("expected %v, got: %v", exp, v)
+	}
+	if _, err := r.Draw(1, pockets[1], pockets[1][:1]...); !errors.Is(err, ErrInvalidDraw) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidDraw, err)
+	}
+}
+
+func TestDealerBringIn(t *testing.T) {
+	if v, exp := []int{
+		StudStreets()[0].PocketUp,
+		StudStreets()[1].PocketUp,
+		StudStreets()[2].PocketUp,
+		StudStreets()[3].PocketUp,
+		StudStreets()[4].PocketUp,
+	}, []int{1, 1, 1, 1, 0}; !reflect.DeepEqual(v, exp) {
+		t.Fatalf("expected %v, got: %v", exp, v)
+	}
+	cards := Must("2s 3s 4s 6s 7s 8s 5h 5c Kd 5d Ah Kc 9c 9d 2h Ts Js Qs As Ks Qh")
+	tests := []struct {
+		typ Type
+		exp []int
+	}{
+		{Stud, []int{1, 2, 2, 2}},
+		{StudHiLo, []int{1, 2, 2, 2}},
+		{Razz, []int{2, 1, 1, 1}},
+		{London, []int{2, 1, 1, 1}},
+	}
+	for _, test := range tests {
+		d := NewShuffledDealer(descs[test.typ], &Deck{v: append([]Card(nil), cards...), l: len(cards)})
+		if n := d.BringIn(); n != -1 {
+			t.Errorf("%s expected -1, got: %d", test.typ, n)
+		}
+		var pockets [][]Card
+		for i := 0; i < 4 && d.Next(); i++ {
+			pockets = d.DealPockets(pockets, 3, true)
+			if n := d.BringIn(); n != test.exp[0] {
+				t.Errorf("%s street %d expected bring in %d, got: %d", test.typ, i, test.exp[0], n)
+			}
+			if n := d.FirstToAct(); n != test.exp[i] {
+				t.Errorf("%s street %d expected first to act %d, got: %d", test.typ, i, test.exp[i], n)
+			}
+		}
+		if v, exp := d.Up(0), Must("5h 5d 9c Ts"); !reflect.DeepEqual(v, exp) {
+			t.Errorf("%s expected %v, got: %v", test.typ, exp, v)
+		}
+		if v, exp := pockets[0], Must("2s 6s 5h 5d 9c Ts"); !reflect.DeepEqual(v, exp) {
+			t.Errorf("%s expected %v, got: %v", test.typ, exp, v)
+		}
+	}
+}
+
+func TestDealerCommunity(t *testing.T) {
+	for _, typ := range []Type{Stud, StudHiLo, Razz, London} {
+		for hands, exp := range map[int]int{7: 0, 8: 1} {
+			d := typ.Dealer(rand.New(rand.NewSource(5)), 1)
+			pockets, board := d.DealAll(hands)
+			if n := len(board); n != exp {
+				t.Fatalf("%s %d expected %d board cards, got: %d", typ, hands, exp, n)
+			}
+			if v := d.Community(); !reflect.DeepEqual(v, board) && exp != 0 {
+				t.Errorf("%s %d expected community %v, got: %v", typ, hands, board, v)
+			}
+			m := make(map[Card]bool)
+			for _, c := range board {
+				m[c] = true
+			}
+			for i, pocket := range pockets {
+				if n, exp := len(pocket), 7-exp; n != exp {
+					t.Errorf("%s %d pocket %d expected %d cards, got: %d", typ, hands, i, exp, n)
+				}
+				for _, c := range pocket {
+					if m[c] {
+						t.Errorf("%s %d pocket %d duplicate card %s", typ, hands, i, c)
+					}
+					m[c] = true
+				}
+			}
+			for i, h := range typ.RankHands(pockets, board) {
+				if h.HiRank == Invalid || len(h.HiBest)+len(h.HiUnused) != 7 {
+					t.Errorf("%s %d hand %d expected 7 card hand, got: %v %v", typ, hands, i, h.HiBest, h.HiUnused)
+				}
+			}
+		}
+	}
+}
+
+func TestCanDeal(t *testing.T) {
+	tests := []struct {
+		typ   Type
+		hands int
+		exp   bool
+	}{
+		{Holdem, 22, true},
+		{Holdem, 23, false},
+		{Stud, 8, true},
+		{Stud, 9, false},
+		{Double, 19, true},
+		{Double, 20, false},
+		{Royal, 6, true},
+		{Royal, 7, false},
+	}
+	for i, test := range tests {
+		desc := descs[test.typ]
+		if b := desc.CanDeal(test.hands); b != test.exp {
+			t.Errorf("test %d expected %t, got: %t", i, test.exp, b)
+		}
+	}
+	for _, typ := range Types() {
+		desc := descs[typ]
+		if !desc.CanDeal(desc.Max) {
+			t.Errorf("%s expected to be able to deal %d hands", typ, desc.Max)
+		}
+	}
+	desc := descs[Stud]
+	desc.Type, desc.Max = 'X'<<8|'s', 9
+	if err := RegisterType(desc); !errors.Is(err, ErrNotEnoughCards) {
+		t.Errorf("expected error %v, got: %v", ErrNotEnoughCards, err)
+	}
+	desc.Max = 20
+	if d := NewDealer(desc, rand.New(rand.NewSource(0)), 1); d != nil {
+		t.Errorf("expected nil dealer for max %d", desc.Max)
+	}
+	d := Stud.Dealer(rand.New(rand.NewSource(0)), 1)
+	if pockets, board := d.DealAll(9); pockets != nil || board != nil {
+		t.Errorf("expected no pockets or board for 9 hands, got: %v %v", pockets, board)
+	}
+	pockets, _ := d.DealAll(8)
+	for i, pocket := range pockets {
+		if len(pocket) != 6 {
+			t.Errorf("expected pocket %d to have 6 cards, got: %v", i, pocket)
+		}
+	}
+	if v := d.Community(); len(v) != 1 {
+		t.Errorf("expected 1 community card, got: %v", v)
+	}
+}
+
+func TestDealerRunOut(t *testing.T) {
+	tests := []struct {
+		typ   Type
+		hands int
+		deal  int
+		n     int
+		board int
+	}{
+		{Holdem, 2, 2, 3, 5},
+		{Holdem, 3, 1, 2, 5},
+		{OmahaHiLo, 3, 2, 2, 5},
+		{Double, 2, 2, 2, 10},
+		{Stud, 4, 2, 2, 0},
+		{Badugi, 2, 1, 2, 0},
+	}
+	for i, test := range tests {
+		d := test.typ.Dealer(rand.New(rand.NewSource(int64(i))), 1)
+		var pockets [][]Card
+		var b1, b2 []Card
+		for j := 0; j < test.deal && d.Next(); j++ {
+			pockets, b1 = d.Deal(pockets, b1, test.hands)
+			if test.typ.Double() {
+				b2 = d.DealBoard(b2, false)
+			}
+		}
+		board := append(append([]Card(nil), b1...), b2...)
+		used := make(map[Card]bool)
+		for _, c := range d.d.v[:d.d.i] {
+			used[c] = true
+		}
+		runouts, shares, err := d.RunOut(pockets, board, test.n)
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if len(runouts) != test.n {
+			t.Fatalf("test %d expected %d runouts, got: %d", i, test.n, len(runouts))
+		}
+		var total float64
+		for _, share := range shares {
+			total += share
+		}
+		if math.Abs(total-1) > 1e-9 {
+			t.Errorf("test %d expected shares to total 1, got: %f", i, total)
+		}
+		exp := make([]float64, test.hands)
+		for j, r := range runouts {
+			if n := len(r.Board); n != test.board {
+				t.Errorf("test %d runout %d expected %d board cards, got: %d", i, j, test.board, n)
+			}
+			if test.typ.Double() {
+				if !reflect.DeepEqual(r.Board[:len(b1)], b1) || !reflect.DeepEqual(r.Board[5:5+len(b2)], b2) {
+					t.Errorf("test %d runout %d expected boards to start with %v %v, got: %v", i, j, b1, b2, r.Board)
+				}
+			} else if 0 < len(b1) && !reflect.DeepEqual(r.Board[:len(b1)], b1) {
+				t.Errorf("test %d runout %d expected board to start with %v, got: %v", i, j, b1, r.Board)
+			}
+			dealt := r.Board[len(b1):]
+			for k, pocket := range r.Pockets {
+				if !reflect.DeepEqual(pocket[:len(pockets[k])], pockets[k]) {
+					t.Errorf("test %d runout %d expected pocket to start with %v, got: %v", i, j, pockets[k], pocket)
+				}
+				dealt = append(dealt, pocket[len(pockets[k]):]...)
+			}
+			if test.typ.Double() {
+				dealt = append(r.Board[len(b1):5], r.Board[5+len(b2):]...)
+			}
+			for _, c := range dealt {
+				if used[c] {
+					t.Errorf("test %d runout %d reused card %s", i, j, c)
+				}
+				used[c] = true
+			}
+			b, lo := r.Board, []*Hand(nil)
+			if test.typ.Double() {
+				b, lo = r.Board[:5], test.typ.RankHands(r.Pockets, r.Board[5:])
+			}
+			hi := test.typ.RankHands(r.Pockets, b)
+			if win := NewWin(hi, lo, test.typ.Low()); !reflect.DeepEqual(win, r.Win) {
+				t.Errorf("test %d runout %d expected %v, got: %v", i, j, win, r.Win)
+			}
+			for k, share := range r.Win.Shares() {
+				exp[k] += share / float64(test.n)
+			}
+		}
+		if !reflect.DeepEqual(shares, exp) {
+			t.Errorf("test %d expected shares %v, got: %v", i, exp, shares)
+		}
+	}
+}
+
+func TestDealerRunOutErrors(t *testing.T) {
+	d := Holdem.Dealer(rand.New(rand.NewSource(0)), 1)
+	d.Next()
+	pockets, _ := d.Deal(nil, nil, 2)
+	if _, _, err := d.RunOut(pockets, nil, 0); !errors.Is(err, ErrInvalidRunouts) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidRunouts, err)
+	}
+	// 48 remaining cards, 8 per runout
+	if _, _, err := d.RunOut(pockets, nil, 7); !errors.Is(err, ErrNotEnoughCards) {
+		t.Errorf("expected error %v, got: %v", ErrNotEnoughCards, err)
+	}
+	if _, _, err := d.RunOut(pockets, nil, 6); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+	d = CrazyPineapple.Dealer(rand.New(rand.NewSource(0)), 1)
+	d.Next()
+	pockets, _ = d.Deal(nil, nil, 2)
+	if _, _, err := d.RunOut(pockets, nil, 2); !errors.Is(err, ErrInvalidDiscard) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidDiscard, err)
+	}
+	d = Double.Dealer(rand.New(rand.NewSource(0)), 1)
+	if _, _, err := d.RunOut(pockets, Must("Ah"), 2); !errors.Is(err, ErrInvalidBoard) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidBoard, err)
+	}
+}
+
+func TestDealerFold(t *testing.T) {
+	d := Showtime.Dealer(rand.New(rand.NewSource(6)), 1)
+	d.Next()
+	pockets, _ := d.Deal(nil, nil, 3)
+	if err := d.Fold(1, pockets[1]); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := d.Fold(1, pockets[1]); !errors.Is(err, ErrInvalidFold) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidFold, err)
+	}
+	if !d.Folded(1) || d.Folded(0) {
+		t.Errorf("expected only player 1 folded")
+	}
+	if v := d.Revealed(1); !reflect.DeepEqual(v, pockets[1]) {
+		t.Errorf("expected revealed %v, got: %v", pockets[1], v)
+	}
+	if err := d.Fold(0, pockets[0]); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if v, exp := d.Dead(), append(append([]Card(nil), pockets[0]...), pockets[1]...); !reflect.DeepEqual(v, exp) {
+		t.Errorf("expected dead %v, got: %v", exp, v)
+	}
+	// folded cards are not shown
+	d = Holdem.Dealer(rand.New(rand.NewSource(6)), 1)
+	d.Next()
+	pockets, _ = d.Deal(nil, nil, 3)
+	if err := d.Fold(1, pockets[1]); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if v := d.Dead(); len(v) != 0 {
+		t.Errorf("expected no dead cards, got: %v", v)
+	}
+	// folded stud players reveal up cards, and are not dealt cards
+	d = Stud.Dealer(rand.New(rand.NewSource(6)), 1)
+	d.Next()
+	pockets, _ = d.Deal(nil, nil, 3)
+	d.Next()
+	pockets, _ = d.Deal(pockets, nil, 3)
+	if err := d.Fold(d.FirstToAct(), pockets[d.FirstToAct()]); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	folded := -1
+	for i := 0; i < 3; i++ {
+		if d.Folded(i) {
+			folded = i
+		}
+	}
+	if v, exp := d.Dead(), d.Up(folded); !reflect.DeepEqual(v, exp) {
+		t.Errorf("expected dead %v, got: %v", exp, v)
+	}
+	d.Next()
+	pockets, _ = d.Deal(pockets, nil, 3)
+	for i, pocket := range pockets {
+		exp := 5
+		if i == folded {
+			exp = 4
+		}
+		if len(pocket) != exp {
+			t.Errorf("player %d expected %d cards, got: %d", i, exp, len(pocket))
+		}
+	}
+	if n := d.FirstToAct(); n == folded || n == -1 {
+		t.Errorf("expected first to act to not be %d, got: %d", folded, n)
+	}
+	// folds are restored
+	buf, err := d.MarshalBinary()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	r := new(Dealer)
+	if err := r.UnmarshalBinary(buf); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !r.Folded(folded) || !reflect.DeepEqual(r.Dead(), d.Dead()) {
+		t.Errorf("expected player %d folded with dead %v, got: %t %v", folded, d.Dead(), r.Folded(folded), r.Dead())
+	}
+	// folded players are ordered last in runouts
+	runouts, shares, err := d.RunOut(pockets, nil, 2)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if shares[folded] != 0 {
+		t.Errorf("expected folded share 0, got: %f", shares[folded])
+	}
+	for i, r := range runouts {
+		if n := r.Win.Hi[len(r.Win.Hi)-1]; n != folded {
+			t.Errorf("runout %d expected %d last, got: %d", i, folded, n)
+		}
+		if n, exp := len(r.Pockets[folded]), 4; n != exp {
+			t.Errorf("runout %d expected %d cards, got: %d", i, exp, n)
+		}
+	}
+}
@@ -0,0 +1,683 @@
+package cardrank
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// Equity is the result of a pocket equity calculation.
+type Equity struct {
+	// Type is the type.
+	Type Type
+	// Count is the number of evaluated runouts.
+	Count int
+	// Hi are the hi outcomes, one per pocket.
+	Hi []Outcome
+	// Lo are the lo outcomes, one per pocket. Only set for Low and Double
+	// types. For Double types, Lo contains the outcomes for the second board.
+	Lo []Outcome
+	// Pot are the accumulated pot shares, one per pocket.
+	Pot []float64
+}
+
+// newEquity creates a new equity for n pockets.
+func newEquity(typ Type, n int, lo bool) *Equity {
+	e := &Equity{
+		Type: typ,
+		Hi:   make([]Outcome, n),
+		Pot:  make([]float64, n),
+	}
+	if lo {
+		e.Lo = make([]Outcome, n)
+	}
+	return e
+}
+
+// Share returns the pocket's share of the pot (its equity), from 0 to 1.
+func (e *Equity) Share(i int) float64 {
+	if total := e.Hi[i].Total(); total != 0 {
+		return e.Pot[i] / total
+	}
+	return 0
+}
+
+// add adds the win with weight w to the equity.
+func (e *Equity) add(win Win, w float64) {
+	e.Count++
+	tally(e.Hi, win.Hi, win.HiPivot, w)
+	if e.Lo != nil {
+		tally(e.Lo, win.Lo, win.LoPivot, w)
+	}
+	for i, share := range win.Shares() {
+		e.Pot[i] += share * w
+	}
+}
+
+// merge merges b into the equity.
+func (e *Equity) merge(b *Equity) {
+	e.Count += b.Count
+	for i := 0; i < len(e.Hi); i++ {
+		e.Hi[i].merge(b.Hi[i])
+		e.Pot[i] += b.Pot[i]
+	}
+	for i := 0; i < len(e.Lo); i++ {
+		e.Lo[i].merge(b.Lo[i])
+	}
+}
+
+// Outcome is a tally of won, tied, and lost runouts.
+type Outcome struct {
+	Win  float64
+	Tie  float64
+	Lose float64
+}
+
+// Total returns the total of the outcome.
+func (o Outcome) Total() float64 {
+	return o.Win + o.Tie + o.Lose
+}
+
+// Shares returns the win, tie, and lose shares of the outcome.
+func (o Outcome) Shares() (float64, float64, float64) {
+	if total := o.Total(); total != 0 {
+		return o.Win / total, o.Tie / total, o.Lose / total
+	}
+	return 0, 0, 0
+}
+
+// merge merges b into the outcome.
+func (o *Outcome) merge(b Outcome) {
+	o.Win += b.Win
+	o.Tie += b.Tie
+	o.Lose += b.Lose
+}
+
+// scale returns the outcome scaled by w.
+func (o Outcome) scale(w float64) Outcome {
+	return Outcome{
+		Win:  o.Win * w,
+		Tie:  o.Tie * w,
+		Lose: o.Lose * w,
+	}
+}
+
+// tally tallies the ordered hands with weight w. When the order is nil (ie,
+// no qualifying low), every hand is tallied as a loss.
+func tally(v []Outcome, order []int, pivot int, w float64) {
+	if order == nil {
+		for i := 0; i < len(v); i++ {
+			v[i].Lose += w
+		}
+		return
+	}
+	for i, j := range order {
+		switch {
+		case pivot <= i:
+			v[j].Lose += w
+		case pivot == 1:
+			v[j].Win += w
+		default:
+			v[j].Tie += w
+		}
+	}
+}
+
+// Equity calculates the equity of the pockets by evaluating n random runouts
+// of the type's remaining deck, after removing the pocket, board, and dead
+// cards from the deck. The deck is shuffled with the shuffler before each
+// runout.
+//
+// Pockets and the board may be partially dealt, and are completed to the
+// counts defined by the type's streets. For types with player discards (see
+// StreetDesc.Discard), pockets are completed to the count remaining after all
+// discards. For Double board types, the board contains the cards of the first
+// board followed by the cards of the second board, each of equal length.
+func (typ Type) Equity(shuffler Shuffler, n int, pockets [][]Card, board, dead []Card) (*Equity, error) {
+	r, err := newRunner(typ, pockets, board, dead)
+	if err != nil {
+		return nil, err
+	}
+	e := r.equity()
+	d := &Deck{
+		v: r.deck,
+		l: len(r.deck),
+	}
+	for i := 0; i < n; i++ {
+		d.Shuffle(shuffler)
+		d.Reset()
+		e.add(r.eval(d.Draw(r.n)), 1)
+	}
+	return e, nil
+}
+
+// ExactEquity calculates the exact equity of the pockets by enumerating every
+// possible completion of the pockets and board from the type's remaining deck,
+// after removing the pocket, board, and dead cards from the deck. See Equity
+// for the pocket and board semantics.
+//
+// The enumeration is split across multiple goroutines, and will stop early,
+// returning the context's error, when the context is closed.
+func (typ Type) ExactEquity(ctx context.Context, pockets [][]Card, board, dead []Card) (*Equity, error) {
+	r, err := newRunner(typ, pockets, board, dead)
+	if err != nil {
+		return nil, err
+	}
+	// collect group sizes
+	var groups []int
+	for _, pocket := range pockets {
+		groups = append(groups, r.pocket-len(pocket))
+	}
+	groups = append(groups, r.count-len(r.board))
+	if r.double {
+		groups = append(groups, r.count-len(r.board2))
+	}
+	// skip leading empty groups
+	for len(groups) != 0 && groups[0] == 0 {
+		groups = groups[1:]
+	}
+	e := r.equity()
+	if len(groups) == 0 {
+		e.add(r.eval(nil), 1)
+		return e, nil
+	}
+	// the first card of the first group is the unit of work
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := 0; i <= len(r.deck)-groups[0]; i++ {
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- i:
+			}
+		}
+	}()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	errs := make([]error, runtime.GOMAXPROCS(0))
+	for n := 0; n < len(errs); n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			w := &walker{
+				ctx:    ctx,
+				r:      r,
+				groups: groups,
+				used:   make([]bool, len(r.deck)),
+				v:      make([]Card, r.n),
+				e:      r.equity(),
+			}
+			for i := range ch {
+				w.used[i], w.v[0] = true, r.deck[i]
+				if errs[n] = w.walk(0, groups[0]-1, i+1, 1); errs[n] != nil {
+					break
+				}
+				w.used[i] = false
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			e.merge(w.e)
+		}(n)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// Equity calculates the equity of the pockets of the players that have not
+// folded (see Dealer.Fold), after removing the publicly known dead cards (see
+// Dealer.Dead) from the deck. The returned equity has outcomes for all
+// pockets, with folded players having no outcomes. See Type.Equity.
+func (d *Dealer) Equity(shuffler Shuffler, n int, pockets [][]Card, board []Card) (*Equity, error) {
+	active, v := d.active(pockets)
+	e, err := d.Type.Equity(shuffler, n, v, board, d.Dead())
+	if err != nil {
+		return nil, err
+	}
+	return e.expand(active, len(pockets)), nil
+}
+
+// ExactEquity calculates the exact equity of the pockets of the players that
+// have not folded, after removing the publicly known dead cards from the deck.
+// See Dealer.Equity and Type.ExactEquity.
+func (d *Dealer) ExactEquity(ctx context.Context, pockets [][]Card, board []Card) (*Equity, error) {
+	active, v := d.active(pockets)
+	e, err := d.Type.ExactEquity(ctx, v, board, d.Dead())
+	if err != nil {
+		return nil, err
+	}
+	return e.expand(active, len(pockets)), nil
+}
+
+// expand expands the equity of the active pockets to n pockets.
+func (e *Equity) expand(active []int, n int) *Equity {
+	if len(active) == n {
+		return e
+	}
+	v := newEquity(e.Type, n, e.Lo != nil)
+	v.Count = e.Count
+	for i, j := range active {
+		v.Hi[j], v.Pot[j] = e.Hi[i], e.Pot[i]
+		if e.Lo != nil {
+			v.Lo[j] = e.Lo[i]
+		}
+	}
+	return v
+}
+
+// RangeEquity is the result of a range equity calculation.
+type RangeEquity struct {
+	// Equity is the aggregate equity, with outcomes and pot shares for each
+	// range, weighted by the combos' weights.
+	*Equity
+	// Combos are the equities of each range's combos, one slice per range,
+	// ordered as returned by Range.Combos.
+	Combos [][]ComboEquity
+}
+
+// newRangeEquity creates a new range equity for the ranges, expanding the
+// ranges' combos after removing the board and dead cards.
+func newRangeEquity(typ Type, ranges []*Range, board, dead []Card) (*RangeEquity, error) {
+	desc, ok := descs[typ]
+	switch {
+	case !ok:
+		return nil, ErrInvalidType
+	case len(ranges) == 0:
+		return nil, ErrInvalidRange
+	}
+	e := &RangeEquity{
+		Equity: newEquity(typ, len(ranges), desc.Low || desc.Double),
+		Combos: make([][]ComboEquity, len(ranges)),
+	}
+	known := append(append([]Card(nil), board...), dead...)
+	for i, r := range ranges {
+		if r == nil {
+			return nil, ErrInvalidRange
+		}
+		for _, combo := range r.Combos(known...) {
+			e.Combos[i] = append(e.Combos[i], ComboEquity{Combo: combo})
+		}
+		if len(e.Combos[i]) == 0 {
+			return nil, ErrInvalidRange
+		}
+	}
+	return e, nil
+}
+
+// add adds the equity of a matchup of combos, weighted by the product of the
+// combos' weights.
+func (e *RangeEquity) add(idx []int, b *Equity) {
+	w := 1.0
+	for i, j := range idx {
+		w *= e.Combos[i][j].Weight
+	}
+	e.Count += b.Count
+	for i, j := range idx {
+		c := &e.Combos[i][j]
+		hi, pot := b.Hi[i].scale(w), b.Pot[i]*w
+		e.Hi[i].merge(hi)
+		e.Pot[i] += pot
+		c.Hi.merge(hi)
+		c.Pot += pot
+		if b.Lo != nil {
+			lo := b.Lo[i].scale(w)
+			e.Lo[i].merge(lo)
+			c.Lo.merge(lo)
+		}
+	}
+}
+
+// matchups calls f for each matchup of combos not sharing a card, stopping
+// when f returns an error.
+func (e *RangeEquity) matchups(f func([]int, [][]Card) error) error {
+	idx, pockets := make([]int, len(e.Combos)), make([][]Card, len(e.Combos))
+	var walk func(int) error
+	walk = func(i int) error {
+		if i == len(e.Combos) {
+			return f(idx, pockets)
+		}
+		for j, c := range e.Combos[i] {
+			if overlaps(c.Pocket, pockets[:i]) {
+				continue
+			}
+			idx[i], pockets[i] = j, c.Pocket
+			if err := walk(i + 1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(0)
+}
+
+// ComboEquity is the equity of a range combo.
+type ComboEquity struct {
+	Combo
+	// Hi is the weighted hi outcome.
+	Hi Outcome
+	// Lo is the weighted lo outcome. Only set for Low and Double types.
+	Lo Outcome
+	// Pot is the weighted accumulated pot share.
+	Pot float64
+}
+
+// Share returns the combo's share of the pot (its equity), from 0 to 1.
+func (c ComboEquity) Share() float64 {
+	if total := c.Hi.Total(); total != 0 {
+		return c.Pot / total
+	}
+	return 0
+}
+
+// RangeEquity calculates the equity of the ranges by evaluating n random
+// matchups and runouts. For each runout, a combo is chosen at random from each
+// range, using the shuffler, and matchups with combos sharing a card are
+// rejected. The runout is dealt from the type's remaining deck after removing
+// the combos', board, and dead cards (see Type.Equity).
+//
+// Combos containing a board or dead card are removed from the ranges.
+// Outcomes are weighted by the product of the matchup's combo weights. Returns
+// ErrInvalidRange when a range has no remaining combos, or when there is no
+// matchup of combos not sharing a card, and ErrInvalidCard when a combo has a
+// card not in the type's deck.
+func (typ Type) RangeEquity(shuffler Shuffler, n int, ranges []*Range, board, dead []Card) (*RangeEquity, error) {
+	e, err := newRangeEquity(typ, ranges, board, dead)
+	if err != nil {
+		return nil, err
+	}
+	// find a valid matchup, and create the runner from it
+	var first [][]Card
+	if err := e.matchups(func(_ []int, pockets [][]Card) error {
+		first = pockets
+		return ErrInvalidRange
+	}); err == nil {
+		return nil, ErrInvalidRange
+	}
+	r, err := newRunner(typ, first, board, dead)
+	if err != nil {
+		return nil, err
+	}
+	d, err := descs[typ].Deck.Without(append(append([]Card(nil), board...), dead...)...)
+	if err != nil {
+		return nil, err
+	}
+	// check combos
+	deck, seen := d.All(), make(map[Card]bool)
+	for _, c := range deck {
+		seen[c] = true
+	}
+	for _, v := range e.Combos {
+		for _, combo := range v {
+			if r.pocket < len(combo.Pocket) {
+				return nil, ErrInvalidPocket
+			}
+			for _, c := range combo.Pocket {
+				if !seen[c] {
+					return nil, ErrInvalidCard
+				}
+			}
+		}
+	}
+	// cards needed to complete the board(s)
+	m := r.count - len(r.board)
+	if r.double {
+		m += r.count - len(r.board2)
+	}
+	idx, pockets := make([]int, len(ranges)), make([][]Card, len(ranges))
+	var known, runout []Card
+	for count := 0; count < n; {
+		for i, v := range e.Combos {
+			idx[i] = pick(shuffler, len(v))
+			pockets[i] = v[idx[i]].Pocket
+		}
+		if hasOverlap(pockets) {
+			continue
+		}
+		// deal the runout from the shuffled deck, skipping the pocket cards
+		r.pockets, r.n, known = pockets, m, known[:0]
+		for _, pocket := range pockets {
+			r.n += r.pocket - len(pocket)
+			known = append(known, pocket...)
+		}
+		if len(deck)-len(known) < r.n {
+			return nil, ErrNotEnoughCards
+		}
+		shuffler.Shuffle(len(deck), func(i, j int) {
+			deck[i], deck[j] = deck[j], deck[i]
+		})
+		runout = runout[:0]
+		for i := 0; len(runout) < r.n; i++ {
+			if j := cardIndex(deck[i], known); j != -1 {
+				known[j] = known[len(known)-1]
+				known = known[:len(known)-1]
+				continue
+			}
+			runout = append(runout, deck[i])
+		}
+		b := r.equity()
+		b.add(r.eval(runout), 1)
+		e.add(idx, b)
+		count++
+	}
+	return e, nil
+}
+
+// ExactRangeEquity calculates the exact equity of the ranges by enumerating
+// every matchup of combos not sharing a card, and calculating the exact equity
+// of each matchup (see Type.ExactEquity). See Type.RangeEquity for the combo
+// and weight semantics.
+func (typ Type) ExactRangeEquity(ctx context.Context, ranges []*Range, board, dead []Card) (*RangeEquity, error) {
+	e, err := newRangeEquity(typ, ranges, board, dead)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.matchups(func(idx []int, pockets [][]Card) error {
+		b, err := typ.ExactEquity(ctx, pockets, board, dead)
+		if err != nil {
+			return err
+		}
+		e.add(idx, b)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if e.Count == 0 {
+		return nil, ErrInvalidRange
+	}
+	return e, nil
+}
+
+// pick returns a random index in [0, n) using the shuffler. Uses the
+// shuffler's Intn method when available (as with math/rand.Rand), otherwise
+// returns the position of the first element after a shuffle.
+func pick(shuffler Shuffler, n int) int {
+	if r, ok := shuffler.(interface{ Intn(int) int }); ok {
+		return r.Intn(n)
+	}
+	i := 0
+	shuffler.Shuffle(n, func(j, k int) {
+		switch i {
+		case j:
+			i = k
+		case k:
+			i = j
+		}
+	})
+	return i
+}
+
+// cardIndex returns the index of the card in v, or -1 when not present.
+func cardIndex(c Card, v []Card) int {
+	for i, d := range v {
+		if c == d {
+			return i
+		}
+	}
+	return -1
+}
+
+// overlaps returns true when the pocket shares a card with any of the
+// pockets.
+func overlaps(pocket []Card, pockets [][]Card) bool {
+	for _, v := range pockets {
+		for _, c := range pocket {
+			if hasCard(c, v...) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasOverlap returns true when any of the pockets share a card.
+func hasOverlap(pockets [][]Card) bool {
+	for i := 1; i < len(pockets); i++ {
+		if overlaps(pockets[i], pockets[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// walker walks the combinations of remaining cards for a runner.
+type walker struct {
+	ctx    context.Context
+	r      *runner
+	groups []int
+	used   []bool
+	v      []Card
+	e      *Equity
+}
+
+// walk recursively walks the combinations of k remaining cards for group g,
+// starting at deck position start and runout position pos. Evaluates the
+// runout once all groups have been completed.
+func (w *walker) walk(g, k, start, pos int) error {
+	if k == 0 {
+		if g++; g == len(w.groups) {
+			w.e.add(w.r.eval(w.v), 1)
+			if w.e.Count%4096 == 0 {
+				return w.ctx.Err()
+			}
+			return nil
+		}
+		return w.walk(g, w.groups[g], 0, pos)
+	}
+	for i := start; i <= len(w.r.deck)-k; i++ {
+		if w.used[i] {
+			continue
+		}
+		w.used[i], w.v[pos] = true, w.r.deck[i]
+		err := w.walk(g, k-1, i+1, pos+1)
+		w.used[i] = false
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runner deals and evaluates runouts for a set of pockets and board.
+type runner struct {
+	typ     Type
+	low     bool
+	double  bool
+	pocket  int
+	count   int
+	pockets [][]Card
+	board   []Card
+	board2  []Card
+	deck    []Card
+	n       int
+}
+
+// newRunner creates a runner for the type, pockets, board, and dead cards.
+func newRunner(typ Type, pockets [][]Card, board, dead []Card) (*runner, error) {
+	desc, ok := descs[typ]
+	switch {
+	case !ok:
+		return nil, ErrInvalidType
+	case len(pockets) == 0:
+		return nil, ErrInvalidPocket
+	}
+	r := &runner{
+		typ:     typ,
+		low:     desc.Low,
+		double:  desc.Double,
+		pockets: pockets,
+		board:   board,
+	}
+	for _, street := range desc.Streets {
+		r.pocket += street.Pocket - street.Discard
+		r.count += street.Board
+	}
+	if r.double {
+		if len(board)%2 != 0 {
+			return nil, ErrInvalidBoard
+		}
+		r.board, r.board2 = board[:len(board)/2], board[len(board)/2:]
+	}
+	known := make([]Card, 0, len(pockets)*r.pocket+len(board)+len(dead))
+	for _, pocket := range pockets {
+		if r.pocket < len(pocket) {
+			return nil, ErrInvalidPocket
+		}
+		r.n += r.pocket - len(pocket)
+		known = append(known, pocket...)
+	}
+	if r.count < len(r.board) {
+		return nil, ErrInvalidBoard
+	}
+	r.n += r.count - len(r.board)
+	if r.double {
+		r.n += r.count - len(r.board2)
+	}
+	d, err := desc.Deck.Without(append(append(known, board...), dead...)...)
+	if err != nil {
+		return nil, err
+	}
+	if r.deck = d.All(); len(r.deck) < r.n {
+		return nil, ErrNotEnoughCards
+	}
+	return r, nil
+}
+
+// equity creates a new equity for the runner.
+func (r *runner) equity() *Equity {
+	return newEquity(r.typ, len(r.pockets), r.low || r.double)
+}
+
+// eval completes the pockets and board(s) in order using v, evaluating the
+// hands and returning the win.
+func (r *runner) eval(v []Card) Win {
+	pockets := make([][]Card, len(r.pockets))
+	for i, pocket := range r.pockets {
+		pockets[i], v = complete(pocket, v, r.pocket)
+	}
+	board, v := complete(r.board, v, r.count)
+	hi := r.typ.RankHands(pockets, board)
+	var lo []*Hand
+	if r.double {
+		board, _ = complete(r.board2, v, r.count)
+		lo = r.typ.RankHands(pockets, board)
+	}
+	return NewWin(hi, lo, r.low)
+}
+
+// complete completes a to n cards using v, returning the completed slice and
+// the remaining cards in v.
+func complete(a, v []Card, n int) ([]Card, []Card) {
+	n -= len(a)
+	b := make([]Card, len(a)+n)
+	copy(b, a)
+	copy(b[len(a):], v[:n])
+	return b, v[n:]
+}
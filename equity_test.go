@@ -0,0 +1,427 @@
+package cardrank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestEquity(t *testing.T) {
+	tests := []struct {
+		typ     Type
+		pockets []string
+		board   string
+		n       int
+		exp     []float64
+		delta   float64
+	}{
+		{Holdem, []string{"Ah As", "Kh Ks"}, "", 20000, []float64{0.82, 0.18}, 0.02},
+		{Holdem, []string{"Ah Kh", "Qs Qc"}, "2h 7h 9c", 20000, []float64{0.54, 0.46}, 0.02},
+		{Holdem, []string{"Ah As", "Kh Ks"}, "Ad 7h 2c 9s 3d", 10, []float64{1, 0}, 0},
+		{Holdem, []string{"Ah Ks", "Ac Kd"}, "2s 7h 9c Td 3s", 10, []float64{0.5, 0.5}, 0},
+		{Short, []string{"Ah Kh", "9s 9c"}, "6h 7h 8c", 10000, []float64{0.46, 0.54}, 0.03},
+		{Omaha, []string{"Ah As Kh Ks", "Qd Qc Jd Jc"}, "", 10000, []float64{0.66, 0.34}, 0.03},
+		{Double, []string{"Ah As", "Kh Ks"}, "Ad 7h 2c Kd 8h 3d", 10000, []float64{0.54, 0.46}, 0.03},
+	}
+	for i, test := range tests {
+		pockets := make([][]Card, len(test.pockets))
+		for j, s := range test.pockets {
+			pockets[j] = Must(s)
+		}
+		e, err := test.typ.Equity(rand.New(rand.NewSource(int64(i))), test.n, pockets, Must(test.board), nil)
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if e.Count != test.n {
+			t.Errorf("test %d expected count %d, got: %d", i, test.n, e.Count)
+		}
+		for j := range pockets {
+			if share := e.Share(j); math.Abs(share-test.exp[j]) > test.delta {
+				t.Errorf("test %d pocket %d expected share %f, got: %f", i, j, test.exp[j], share)
+			}
+		}
+		if (e.Lo != nil) != test.typ.Double() {
+			t.Errorf("test %d expected lo outcomes only for double types", i)
+		}
+	}
+}
+
+func TestEquityHiLo(t *testing.T) {
+	pockets := [][]Card{
+		Must("Ah 2h Kc Qc"),
+		Must("As Ks Jd Td"),
+	}
+	e, err := OmahaHiLo.Equity(rand.New(rand.NewSource(0)), 1, pockets, Must("3d 4c 8s Kd Ts"), nil)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	// pocket 0 wins the low, pocket 1 wins the high
+	if e.Lo[0].Win != 1 || e.Lo[1].Lose != 1 {
+		t.Errorf("expected pocket 0 to win lo, got: %v", e.Lo)
+	}
+	if e.Hi[1].Win != 1 || e.Hi[0].Lose != 1 {
+		t.Errorf("expected pocket 1 to win hi, got: %v", e.Hi)
+	}
+	if e.Share(0) != 0.5 || e.Share(1) != 0.5 {
+		t.Errorf("expected split pot, got: %f, %f", e.Share(0), e.Share(1))
+	}
+	// no low, pocket 1 scoops
+	e, err = OmahaHiLo.Equity(rand.New(rand.NewSource(0)), 1, pockets, Must("9d Tc 8s Kd Ts"), nil)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if e.Lo[0].Lose != 1 || e.Lo[1].Lose != 1 {
+		t.Errorf("expected no lo, got: %v", e.Lo)
+	}
+	if e.Share(1) != 1 {
+		t.Errorf("expected pocket 1 to scoop, got: %f", e.Share(1))
+	}
+	win, tie, lose := e.Hi[1].Shares()
+	if win != 1 || tie != 0 || lose != 0 {
+		t.Errorf("expected pocket 1 hi shares 1, 0, 0, got: %f, %f, %f", win, tie, lose)
+	}
+}
+
+func TestEquityErrors(t *testing.T) {
+	tests := []struct {
+		typ     Type
+		pockets []string
+		board   string
+		dead    string
+		err     error
+	}{
+		{Type(0), []string{"Ah As"}, "", "", ErrInvalidType},
+		{Holdem, nil, "", "", ErrInvalidPocket},
+		{Holdem, []string{"Ah As Ad"}, "", "", ErrInvalidPocket},
+		{Holdem, []string{"Ah As"}, "2c 3c 4c 5c 6c 7c", "", ErrInvalidBoard},
+		{Holdem, []string{"Ah As", "As Ks"}, "", "", ErrDuplicateCard},
+		{Holdem, []string{"Ah As", "Kh Ks"}, "", "Ah", ErrDuplicateCard},
+		{Short, []string{"Ah As", "2h 2s"}, "", "", ErrInvalidCard},
+		{Double, []string{"Ah As"}, "2c 3c 4c", "", ErrInvalidBoard},
+		{Stud, []string{"Ah", "Ac", "Ad", "As", "Kh", "Kc", "Kd", "Ks"}, "", "", ErrNotEnoughCards},
+	}
+	for i, test := range tests {
+		pockets := make([][]Card, len(test.pockets))
+		for j, s := range test.pockets {
+			pockets[j] = Must(s)
+		}
+		_, err := test.typ.Equity(rand.New(rand.NewSource(0)), 1, pockets, Must(test.board), Must(test.dead))
+		if !errors.Is(err, test.err) {
+			t.Errorf("test %d expected error %v, got: %v", i, test.err, err)
+		}
+	}
+}
+
+func TestExactEquity(t *testing.T) {
+	tests := []struct {
+		typ     Type
+		pockets []string
+		board   string
+		dead    string
+		count   int
+	}{
+		{Holdem, []string{"Ah Kh", "Qs Qc"}, "2h 7h 9c Td", "", 44},
+		{Holdem, []string{"Ah Kh", "Qs Qc", "9s 8s"}, "2h 7h 9c", "Jc", 861},
+		{Omaha, []string{"Ah As Kh Ks", "Qd Qc Jd Jc"}, "2h 7h 9c Td", "", 40},
+		{OmahaHiLo, []string{"Ah 2h Kc Qc", "As 3s Jd Td"}, "4d 5c 9s", "", 820},
+		{Double, []string{"Ah As", "Kh Ks"}, "Ad 7h 2c Kd Qh 3d 8c 9c", "", 1560},
+		{Stud, []string{"Ah As 2c 3d 4h 5h", "Kh Ks Kd 7c 8c 9c"}, "", "", 1560},
+	}
+	for i, test := range tests {
+		pockets := make([][]Card, len(test.pockets))
+		for j, s := range test.pockets {
+			pockets[j] = Must(s)
+		}
+		board, dead := Must(test.board), Must(test.dead)
+		e, err := test.typ.ExactEquity(context.Background(), pockets, board, dead)
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if e.Count != test.count {
+			t.Errorf("test %d expected count %d, got: %d", i, test.count, e.Count)
+		}
+		// compare against a serial enumeration
+		r, err := newRunner(test.typ, pockets, board, dead)
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		exp := r.equity()
+		var f func(int, []Card)
+		f = func(start int, v []Card) {
+			if len(v) == r.n {
+				for _, u := range permuteGroups(r, v) {
+					exp.add(r.eval(u), 1)
+				}
+				return
+			}
+			for j := start; j < len(r.deck); j++ {
+				f(j+1, append(v, r.deck[j]))
+			}
+		}
+		f(0, nil)
+		if exp.Count != e.Count {
+			t.Fatalf("test %d expected count %d, got: %d", i, exp.Count, e.Count)
+		}
+		for j := range pockets {
+			if math.Abs(exp.Share(j)-e.Share(j)) > 1e-9 || exp.Hi[j] != e.Hi[j] {
+				t.Errorf("test %d pocket %d expected %v %f, got: %v %f", i, j, exp.Hi[j], exp.Share(j), e.Hi[j], e.Share(j))
+			}
+		}
+	}
+}
+
+// permuteGroups returns every distinct assignment of the cards in v to the
+// runner's pockets and board(s).
+func permuteGroups(r *runner, v []Card) [][]Card {
+	var sizes []int
+	for _, pocket := range r.pockets {
+		sizes = append(sizes, r.pocket-len(pocket))
+	}
+	sizes = append(sizes, r.count-len(r.board))
+	if r.double {
+		sizes = append(sizes, r.count-len(r.board2))
+	}
+	var res [][]Card
+	var f func(int, []Card, []Card)
+	f = func(g int, rem, acc []Card) {
+		if g == len(sizes) {
+			res = append(res, append([]Card(nil), acc...))
+			return
+		}
+		var h func(int, []Card, []Card)
+		h = func(start int, pick, skip []Card) {
+			if len(pick) == sizes[g] {
+				f(g+1, append(append([]Card(nil), skip...), rem[start:]...), append(append([]Card(nil), acc...), pick...))
+				return
+			}
+			for j := start; j < len(rem); j++ {
+				h(j+1, append(append([]Card(nil), pick...), rem[j]), append(append([]Card(nil), skip...), rem[start:j]...))
+			}
+		}
+		h(0, nil, nil)
+	}
+	f(0, v, nil)
+	return res
+}
+
+func TestExactEquityCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	pockets := [][]Card{
+		Must("Ah As Kh Ks"),
+		Must("Qd Qc Jd Jc"),
+		Must("Td Tc 9d 9c"),
+	}
+	if _, err := Omaha.ExactEquity(ctx, pockets, nil, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error %v, got: %v", context.Canceled, err)
+	}
+}
+
+func TestDealerEquity(t *testing.T) {
+	d := Showtime.Dealer(rand.New(rand.NewSource(0)), 1)
+	d.Next()
+	pockets := [][]Card{
+		Must("Ah As"),
+		Must("Kh Ks"),
+		Must("Qh Qs"),
+	}
+	board := Must("2c 7d 9h")
+	if err := d.Fold(0, pockets[0]); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	e, err := d.ExactEquity(context.Background(), pockets, board)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	exp, err := Showtime.ExactEquity(context.Background(), pockets[1:], board, pockets[0])
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if e.Count != exp.Count || e.Count != 903 {
+		t.Errorf("expected count %d, got: %d", exp.Count, e.Count)
+	}
+	if e.Share(0) != 0 || e.Hi[0].Total() != 0 {
+		t.Errorf("expected no equity for folded pocket, got: %f", e.Share(0))
+	}
+	for i := 1; i < len(pockets); i++ {
+		if e.Share(i) != exp.Share(i-1) {
+			t.Errorf("pocket %d expected share %f, got: %f", i, exp.Share(i-1), e.Share(i))
+		}
+	}
+	if _, err := d.Equity(rand.New(rand.NewSource(0)), 100, pockets, board); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+}
+
+func TestExactRangeEquity(t *testing.T) {
+	tests := []struct {
+		typ    Type
+		ranges []string
+		board  string
+		dead   string
+		count  int
+	}{
+		{Holdem, []string{"AA", "KK:0.5, QQ"}, "2h 7h 9c Td", "", 6 * 12 * 44},
+		{Holdem, []string{"AK, QQ", "AA, KQs:0.25"}, "As 7h 9c Td", "2c", (12 + 36 + 18 + 12) * 43},
+		{Holdem, []string{"AhKh, JJ", "AsKs", "AKs"}, "2h 7h 9c Td", "", (2 + 6*3) * 42},
+		{OmahaHiLo, []string{"Ah2hKcQc, AcAd2c3d", "As3sJdTd"}, "4d 5c 9s", "", 2 * 820},
+	}
+	for i, test := range tests {
+		ranges := make([]*Range, len(test.ranges))
+		for j, s := range test.ranges {
+			ranges[j] = MustRange(s)
+		}
+		board, dead := Must(test.board), Must(test.dead)
+		e, err := test.typ.ExactRangeEquity(context.Background(), ranges, board, dead)
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if e.Count != test.count {
+			t.Errorf("test %d expected count %d, got: %d", i, test.count, e.Count)
+		}
+		if (e.Lo != nil) != test.typ.Low() {
+			t.Errorf("test %d expected lo outcomes only for low types", i)
+		}
+		// compare against the shares weighted by hand, from the exact equity
+		// of each matchup
+		combos := make([][]Combo, len(ranges))
+		for j, r := range ranges {
+			combos[j] = r.Combos(append(board, dead...)...)
+		}
+		n := len(ranges)
+		pot, total, win, lo := make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+		cpot, ctotal := make(map[string]float64), make(map[string]float64)
+		var f func(int, float64, [][]Card)
+		f = func(j int, w float64, pockets [][]Card) {
+			if j == len(ranges) {
+				b, err := test.typ.ExactEquity(context.Background(), pockets, board, dead)
+				if err != nil {
+					t.Fatalf("test %d expected no error, got: %v", i, err)
+				}
+				for k, pocket := range pockets {
+					key := fmt.Sprintf("%d %v", k, pocket)
+					pot[k] += w * b.Pot[k]
+					total[k] += w * b.Hi[k].Total()
+					win[k] += w * b.Hi[k].Win
+					if b.Lo != nil {
+						lo[k] += w * b.Lo[k].Win
+					}
+					cpot[key] += w * b.Pot[k]
+					ctotal[key] += w * b.Hi[k].Total()
+				}
+				return
+			}
+		loop:
+			for _, combo := range combos[j] {
+				for _, c := range combo.Pocket {
+					for _, pocket := range pockets {
+						if contains(pocket, c) {
+							continue loop
+						}
+					}
+				}
+				f(j+1, w*combo.Weight, append(pockets, combo.Pocket))
+			}
+		}
+		f(0, 1, nil)
+		for j := range ranges {
+			if exp := pot[j] / total[j]; math.Abs(exp-e.Share(j)) > 1e-9 || math.Abs(win[j]-e.Hi[j].Win) > 1e-9 {
+				t.Errorf("test %d range %d expected %f %f, got: %f %f", i, j, win[j], exp, e.Hi[j].Win, e.Share(j))
+			}
+			if e.Lo != nil && math.Abs(lo[j]-e.Lo[j].Win) > 1e-9 {
+				t.Errorf("test %d range %d expected lo %f, got: %f", i, j, lo[j], e.Lo[j].Win)
+			}
+			if len(e.Combos[j]) != len(combos[j]) {
+				t.Fatalf("test %d range %d expected %d combos, got: %d", i, j, len(combos[j]), len(e.Combos[j]))
+			}
+			for _, c := range e.Combos[j] {
+				key := fmt.Sprintf("%d %v", j, c.Pocket)
+				if exp := cpot[key] / ctotal[key]; math.Abs(exp-c.Share()) > 1e-9 {
+					t.Errorf("test %d range %d combo %v expected %f, got: %f", i, j, c.Pocket, exp, c.Share())
+				}
+			}
+		}
+	}
+	// complete board: AA beats KK (weighted 0.5) in 36 matchups, and loses to
+	// 22 in 18 matchups
+	e, err := Holdem.ExactRangeEquity(context.Background(), []*Range{MustRange("AA"), MustRange("KK:0.5, 22")}, Must("2h 7h 9c Td 3s"), nil)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if e.Count != 36+18 {
+		t.Errorf("expected count %d, got: %d", 36+18, e.Count)
+	}
+	for j, exp := range []float64{0.5, 0.5} {
+		if s := e.Share(j); s != exp {
+			t.Errorf("range %d expected %f, got: %f", j, exp, s)
+		}
+	}
+	for _, c := range e.Combos[1] {
+		exp := 0.0
+		if c.Pocket[0].Rank() == Two {
+			exp = 1
+		}
+		if s := c.Share(); s != exp {
+			t.Errorf("combo %v expected %f, got: %f", c.Pocket, exp, s)
+		}
+	}
+}
+
+func TestRangeEquity(t *testing.T) {
+	ranges := []*Range{MustRange("AA"), MustRange("KK, QQ:0.5")}
+	for _, shuffler := range []Shuffler{
+		rand.New(rand.NewSource(0)),
+		NewFairShuffler([]byte("server seed")),
+	} {
+		e, err := Holdem.RangeEquity(shuffler, 20000, ranges, nil, nil)
+		if err != nil {
+			t.Fatalf("%T expected no error, got: %v", shuffler, err)
+		}
+		if e.Count != 20000 {
+			t.Errorf("%T expected count %d, got: %d", shuffler, 20000, e.Count)
+		}
+		if share, exp := e.Share(0), 0.81; math.Abs(share-exp) > 0.02 {
+			t.Errorf("%T expected share %f, got: %f", shuffler, exp, share)
+		}
+		for i, c := range e.Combos[1] {
+			if exp := 0.18; math.Abs(c.Share()-exp) > 0.05 {
+				t.Errorf("%T combo %d %v expected share %f, got: %f", shuffler, i, c.Pocket, exp, c.Share())
+			}
+			if c.Hi.Total() == 0 {
+				t.Errorf("%T combo %d %v expected to be picked", shuffler, i, c.Pocket)
+			}
+		}
+	}
+}
+
+func TestRangeEquityErrors(t *testing.T) {
+	tests := []struct {
+		typ    Type
+		ranges []string
+		board  string
+		err    error
+	}{
+		{Holdem, []string{"AA", "AA"}, "As Ah 2c", ErrInvalidRange},
+		{Holdem, []string{"AA", "KK"}, "As Ah 2c Ad", ErrInvalidRange},
+		{Holdem, []string{"AA", "AAKK"}, "", ErrInvalidPocket},
+		{Short, []string{"AA", "22"}, "", ErrInvalidCard},
+		{Type(0), []string{"AA", "KK"}, "", ErrInvalidType},
+		{Holdem, nil, "", ErrInvalidRange},
+	}
+	for i, test := range tests {
+		ranges := make([]*Range, len(test.ranges))
+		for j, s := range test.ranges {
+			ranges[j] = MustRange(s)
+		}
+		board := Must(test.board)
+		if _, err := test.typ.RangeEquity(rand.New(rand.NewSource(0)), 10, ranges, board, nil); !errors.Is(err, test.err) {
+			t.Errorf("test %d expected error %v, got: %v", i, test.err, err)
+		}
+		if _, err := test.typ.ExactRangeEquity(context.Background(), ranges, board, nil); !errors.Is(err, test.err) {
+			t.Errorf("test %d expected exact error %v, got: %v", i, test.err, err)
+		}
+	}
+}
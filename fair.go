@@ -0,0 +1,182 @@
+package cardrank
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+)
+
+// FairShuffler is a provably fair, commit-reveal deck shuffler.
+//
+// Before a deal, the server publishes the shuffler's Commitment (the
+// hex-encoded SHA-256 hash of the server seed), and collects any client seeds.
+// After the hand, the server reveals its seed, allowing any player to verify
+// the commitment and reproduce the deck's order with VerifyFair.
+//
+// Shuffles are a Fisher-Yates shuffle, drawing random values from a stream of
+// HMAC-SHA256 blocks keyed with the server seed. Block i (starting at 0) is
+// the HMAC of each client seed, in order, prefixed with its length as a 4 byte
+// big-endian uint32, followed by i as an 8 byte big-endian uint64. Each block
+// is consumed as 4 big-endian uint64 values. A value v in [0, n) is produced
+// by rejection sampling: values at or above 2^64 - (2^64 % n) are discarded,
+// otherwise v % n is used. For each i from n-1 down to 1, the cards at
+// positions i and v in [0, i+1) are swapped. The stream continues across
+// multiple shuffles (see Deck.ShuffleN).
+type FairShuffler struct {
+	seed    []byte
+	clients [][]byte
+	counter uint64
+	buf     []byte
+}
+
+// NewFairShuffler creates a new provably fair shuffler for the server seed and
+// client seeds.
+func NewFairShuffler(seed []byte, clients ...[]byte) *FairShuffler {
+	return &FairShuffler{
+		seed:    seed,
+		clients: clients,
+	}
+}
+
+// NewFairSeed creates a new random 32 byte server seed using crypto/rand.
+func NewFairSeed() ([]byte, error) {
+	seed := make([]byte, 32)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	return seed, nil
+}
+
+// FairCommitment returns the hex-encoded SHA-256 hash of the server seed.
+func FairCommitment(seed []byte) string {
+	h := sha256.Sum256(seed)
+	return hex.EncodeToString(h[:])
+}
+
+// Commitment returns the shuffler's commitment, the hex-encoded SHA-256 hash
+// of the server seed. Publish before dealing.
+func (s *FairShuffler) Commitment() string {
+	return FairCommitment(s.seed)
+}
+
+// Seed returns the shuffler's server seed. Reveal after the hand.
+func (s *FairShuffler) Seed() []byte {
+	return s.seed
+}
+
+// Shuffle satisfies the Shuffler interface.
+func (s *FairShuffler) Shuffle(n int, swap func(int, int)) {
+	if n < 0 {
+		panic("invalid argument to Shuffle")
+	}
+	for i := n - 1; 0 < i; i-- {
+		swap(i, int(s.intn(uint64(i+1))))
+	}
+}
+
+// intn returns a uniform random value in [0, n).
+func (s *FairShuffler) intn(n uint64) uint64 {
+	// reject values at or above 2^64 - (2^64 % n)
+	limit := ^uint64(0) - -n%n
+	for {
+		if v := s.uint64(); v <= limit {
+			return v % n
+		}
+	}
+}
+
+// uint64 returns the next value from the stream.
+func (s *FairShuffler) uint64() uint64 {
+	if len(s.buf) == 0 {
+		s.buf = s.block()
+	}
+	v := binary.BigEndian.Uint64(s.buf)
+	s.buf = s.buf[8:]
+	return v
+}
+
+// block returns the next block from the stream.
+func (s *FairShuffler) block() []byte {
+	mac := hmac.New(sha256.New, s.seed)
+	var b [8]byte
+	for _, client := range s.clients {
+		binary.BigEndian.PutUint32(b[:4], uint32(len(client)))
+		mac.Write(b[:4])
+		mac.Write(client)
+	}
+	binary.BigEndian.PutUint64(b[:], s.counter)
+	mac.Write(b[:])
+	s.counter++
+	return mac.Sum(nil)
+}
+
+// fairVersion is the fair shuffler binary encoding version.
+const fairVersion = 1
+
+// MarshalBinary satisfies the encoding.BinaryMarshaler interface, allowing the
+// shuffler's position in the stream to be saved with a dealer's state (see
+// Dealer.MarshalBinary).
+//
+// The encoding is a version byte, followed by the server seed, the client
+// seeds as a uvarint count followed by each client seed, the block counter as
+// a uvarint, and the count of unused bytes of the current block as a byte.
+// Seeds are encoded as a uvarint length followed by the seed. The encoding
+// contains the server seed, and must be kept private until the seed is
+// revealed.
+func (s *FairShuffler) MarshalBinary() ([]byte, error) {
+	buf := appendBytes([]byte{fairVersion}, s.seed)
+	buf = binary.AppendUvarint(buf, uint64(len(s.clients)))
+	for _, client := range s.clients {
+		buf = appendBytes(buf, client)
+	}
+	buf = binary.AppendUvarint(buf, s.counter)
+	return append(buf, byte(len(s.buf))), nil
+}
+
+// UnmarshalBinary satisfies the encoding.BinaryUnmarshaler interface.
+func (s *FairShuffler) UnmarshalBinary(buf []byte) error {
+	r := &stateReader{buf: buf}
+	if r.byte() != fairVersion {
+		return ErrInvalidShuffler
+	}
+	seed, n := r.bytes(), r.uvarint()
+	var clients [][]byte
+	for i := uint64(0); i < n && !r.err; i++ {
+		clients = append(clients, r.bytes())
+	}
+	counter, unused := r.uvarint(), int(r.byte())
+	switch {
+	case r.err, len(r.buf) != 0, sha256.Size < unused, unused%8 != 0, unused != 0 && counter == 0:
+		return ErrInvalidShuffler
+	}
+	*s = FairShuffler{
+		seed:    seed,
+		clients: clients,
+		counter: counter,
+	}
+	// regenerate the current block
+	if unused != 0 {
+		s.counter--
+		s.buf = s.block()[sha256.Size-unused:]
+	}
+	return nil
+}
+
+// VerifyFair verifies the commitment for the revealed server seed, returning
+// the type's deck shuffled n times with a FairShuffler for the server seed and
+// client seeds. The returned cards are the same as the Dealer.All of a dealer
+// created with the same shuffler and n. Returns ErrInvalidCommitment when the
+// commitment does not match the server seed.
+func VerifyFair(typ Type, commitment string, seed []byte, n int, clients ...[]byte) ([]Card, error) {
+	if subtle.ConstantTimeCompare([]byte(commitment), []byte(FairCommitment(seed))) != 1 {
+		return nil, ErrInvalidCommitment
+	}
+	d := typ.Dealer(NewFairShuffler(seed, clients...), n)
+	if d == nil {
+		return nil, ErrInvalidType
+	}
+	return d.All(), nil
+}
@@ -0,0 +1,113 @@
+package cardrank
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFairShuffler(t *testing.T) {
+	seed := []byte("server seed")
+	clients := [][]byte{[]byte("alice"), []byte("bob")}
+	s := NewFairShuffler(seed, clients...)
+	commitment := s.Commitment()
+	if exp := "a4e53dc2f480b8fce6fe688b1317658b446299df23ad533394406427c8c19557"; commitment != exp {
+		t.Errorf("expected commitment %s, got: %s", exp, commitment)
+	}
+	d := Holdem.Dealer(s, 3)
+	d.DealAll(2)
+	v, err := VerifyFair(Holdem, commitment, seed, 3, clients...)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if exp := d.All(); !reflect.DeepEqual(v, exp) {
+		t.Errorf("expected %v, got: %v", exp, v)
+	}
+	if v, exp := v[:5], Must("9s 9c 9h As 7h"); !reflect.DeepEqual(v, exp) {
+		t.Errorf("expected %v, got: %v", exp, v)
+	}
+	// different client seeds, or order, produce a different deck
+	for i, c := range [][][]byte{
+		{[]byte("alice")},
+		{[]byte("bob"), []byte("alice")},
+		{[]byte("alic"), []byte("ebob")},
+	} {
+		w, err := VerifyFair(Holdem, commitment, seed, 3, c...)
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if reflect.DeepEqual(v, w) {
+			t.Errorf("test %d expected different deck", i)
+		}
+	}
+	if _, err := VerifyFair(Holdem, commitment, []byte("other seed"), 3, clients...); !errors.Is(err, ErrInvalidCommitment) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidCommitment, err)
+	}
+	if _, err := VerifyFair(Type(0), commitment, seed, 3, clients...); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidType, err)
+	}
+}
+
+func TestFairShufflerMarshal(t *testing.T) {
+	s := NewFairShuffler([]byte("server seed"), []byte("alice"), []byte("bob"))
+	// consume part of a block
+	s.Shuffle(7, func(int, int) {})
+	for i := 0; i < 3; i++ {
+		buf, err := s.MarshalBinary()
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		u := new(FairShuffler)
+		if err := u.UnmarshalBinary(buf); err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if v, err := u.MarshalBinary(); err != nil || !reflect.DeepEqual(v, buf) {
+			t.Fatalf("test %d expected %v, got: %v (%v)", i, buf, v, err)
+		}
+		a, b := Holdem.Dealer(s, 1), Holdem.Dealer(u, 1)
+		if v, exp := b.All(), a.All(); !reflect.DeepEqual(v, exp) {
+			t.Errorf("test %d expected %v, got: %v", i, exp, v)
+		}
+	}
+	buf, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	for i, v := range [][]byte{
+		nil,
+		buf[:len(buf)-1],
+		append(append([]byte(nil), buf...), 0),
+		append(append([]byte(nil), buf[:len(buf)-1]...), 7),
+		append([]byte{0}, buf[1:]...),
+	} {
+		if err := new(FairShuffler).UnmarshalBinary(v); !errors.Is(err, ErrInvalidShuffler) {
+			t.Errorf("test %d expected error %v, got: %v", i, ErrInvalidShuffler, err)
+		}
+	}
+}
+
+func TestFairShufflerUniform(t *testing.T) {
+	seed, err := NewFairSeed()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	s := NewFairShuffler(seed)
+	const n, count = 5, 60000
+	var counts [n][n]int
+	for i := 0; i < count; i++ {
+		v := [n]int{0, 1, 2, 3, 4}
+		s.Shuffle(n, func(i, j int) {
+			v[i], v[j] = v[j], v[i]
+		})
+		for pos, x := range v {
+			counts[x][pos]++
+		}
+	}
+	for x := 0; x < n; x++ {
+		for pos := 0; pos < n; pos++ {
+			if c, exp := counts[x][pos], count/n; c < exp*9/10 || exp*11/10 < c {
+				t.Errorf("expected %d at position %d about %d times, got: %d", x, pos, exp, c)
+			}
+		}
+	}
+}
@@ -72,6 +72,12 @@ func (h *Hand) Hand() []Card {
 
 // Fixed returns the hand's fixed rank.
 func (h *Hand) Fixed() HandRank {
+	switch h.Type {
+	case Lowball, LowballTriple, AceSix, AceSixTriple, London:
+		return lowballCactus(h.HiRank).Fixed()
+	case DeucesWild, JokersWild, DrawBug:
+		return WildRank(h.HiRank).Fixed()
+	}
 	return h.HiRank.Fixed()
 }
 
@@ -121,27 +127,60 @@ func (h *Hand) Format(f fmt.State, verb rune) {
 //	Two Pair, Nines over Sixes, kicker Jack
 //	Pair, Aces, kickers King, Queen, Nine
 //	Nothing, Seven-high, kickers Six, Five, Three, Two
+//
+// Soko hands additionally describe four-card flushes and straights:
+//
+//	Four Flush, Ace-high, kicker Two
+//	Four Straight, Eight-high, kicker Five
+//
+// Wild hands additionally describe five of a kind:
+//
+//	Five of a Kind, Aces
 func (h *Hand) Description() string {
+	if h.HiRank == Invalid {
+		return "Invalid"
+	}
 	r := h.HiRank
 	switch {
 	case h.Type == Badugi,
-		h.Type == Razz && h.HiRank < rankLowMax:
+		h.Type == Badeucy,
+		h.Type == Badacey,
+		h.Type == Razz && h.HiRank < rankLowMax,
+		h.Type == California && h.HiRank < rankLowMax:
 		s := make([]string, len(h.HiBest))
 		for i := 0; i < len(h.HiBest); i++ {
 			s[i] = h.HiBest[i].Rank().Name()
 		}
 		return strings.Join(s, ", ") + "-low"
-	case h.Type == Razz:
+	case h.Type == Razz, h.Type == California:
 		r = Invalid - r
+	case h.Type == DeucesWild, h.Type == JokersWild, h.Type == DrawBug:
+		if r <= wildShift {
+			return fmt.Sprintf("Five of a Kind, %P", h.HiBest[0])
+		}
+		r -= wildShift
+	case h.Type == Soko:
+		switch {
+		case r <= TwoPair:
+		case r <= sokoFourFlush:
+			return fmt.Sprintf("Four Flush, %N-high, kicker %N", h.HiBest[0], h.HiBest[4])
+		case r <= sokoFourStraight:
+			return fmt.Sprintf("Four Straight, %N-high, kicker %N", h.HiBest[0], h.HiBest[4])
+		default:
+			r -= sokoShift
+		}
 	case h.Type == Lowball,
-		h.Type == LowballTriple:
-		if r = rankMax - r; r > Pair {
+		h.Type == LowballTriple,
+		h.Type == AceSix,
+		h.Type == AceSixTriple,
+		h.Type == London:
+		if r = lowballCactus(r); r > Pair {
 			s := make([]string, len(h.HiBest))
 			for i := 0; i < len(h.HiBest); i++ {
 				s[i] = h.HiBest[i].Rank().Name()
 			}
 			str := strings.Join(s, ", ") + "-low"
-			if r == Nothing {
+			if r == Nothing && (h.Type == Lowball || h.Type == LowballTriple) {
 				str += ", Wheel"
 			}
 			return str
@@ -177,10 +216,28 @@ func (h *Hand) Description() string {
 }
 
 // LowDescription describes the hands best-five low cards.
+//
+// For split pot types having a separately evaluated draw hand (ie, Badeucy,
+// Badacey, and Dramaha), describes the draw hand using the matching type's
+// description.
 func (h *Hand) LowDescription() string {
 	if h.LoRank == Invalid {
 		return "None"
 	}
+	var typ Type
+	switch h.Type {
+	case Badeucy, DramahaDeuce:
+		typ = Lowball
+	case Badacey:
+		typ = Razz
+	case Dramaha:
+		typ = Holdem
+	case Dramadugi:
+		typ = Badugi
+	}
+	if typ != 0 {
+		return (&Hand{Type: typ, HiRank: h.LoRank, HiBest: h.LoBest}).Description()
+	}
 	s := make([]string, len(h.LoBest))
 	for i := 0; i < len(h.LoBest); i++ {
 		s[i] = h.LoBest[i].Rank().Name()
@@ -301,6 +358,24 @@ func (win Win) Scoop() bool {
 	return false
 }
 
+// Shares returns each hand's fractional share of the pot. When there is a
+// winning lo (or second board) hand, the pot is split in half between the hi
+// and lo winners.
+func (win Win) Shares() []float64 {
+	v := make([]float64, len(win.Hi))
+	hi := 1.0
+	if win.LoPivot != 0 {
+		hi = 0.5
+	}
+	for i := 0; i < win.HiPivot; i++ {
+		v[win.Hi[i]] += hi / float64(win.HiPivot)
+	}
+	for i := 0; i < win.LoPivot; i++ {
+		v[win.Lo[i]] += (1 - hi) / float64(win.LoPivot)
+	}
+	return v
+}
+
 // HiVerb returns the win verb.
 func (win Win) HiVerb() string {
 	return WinVerb(win.HiPivot, win.Scoop())
@@ -92,6 +92,7 @@ func TestHandRankString(t *testing.T) {
 		{0x012a, "Full House"},
 		{0x0013, "Four of a Kind"},
 		{0x0001, "Straight Flush"},
+		{0x0000, "Five of a Kind"},
 	}
 	for i, test := range tests {
 		if s := test.r.String(); s != test.exp {
@@ -0,0 +1,215 @@
+package cardrank
+
+// OutResult is an out result.
+type OutResult uint8
+
+// Out results.
+const (
+	// OutNone is no win or tie.
+	OutNone OutResult = iota
+	// OutTie is a tie.
+	OutTie
+	// OutWin is a win.
+	OutWin
+)
+
+// String satisfies the fmt.Stringer interface.
+func (res OutResult) String() string {
+	switch res {
+	case OutTie:
+		return "tie"
+	case OutWin:
+		return "win"
+	}
+	return "none"
+}
+
+// Out is a card that wins or ties the hi or lo for a pocket when dealt as the
+// next board card.
+type Out struct {
+	// Card is the out.
+	Card Card
+	// Fixed is the pocket's fixed hi rank after the card is dealt (see
+	// HandRank.Fixed).
+	Fixed HandRank
+	// Hi is the pocket's hi result.
+	Hi OutResult
+	// Lo is the pocket's lo result. Only set for Low types.
+	Lo OutResult
+}
+
+// Scoop returns true when the out wins or ties both the hi and lo.
+func (out Out) Scoop() bool {
+	return out.Hi != OutNone && out.Lo != OutNone
+}
+
+// Outs is the result of an outs calculation.
+type Outs struct {
+	// Type is the type.
+	Type Type
+	// Count is the number of unseen cards evaluated.
+	Count int
+	// Outs are the outs, in deck order.
+	Outs []Out
+}
+
+// Cards returns the out cards.
+func (o *Outs) Cards() []Card {
+	v := make([]Card, len(o.Outs))
+	for i, out := range o.Outs {
+		v[i] = out.Card
+	}
+	return v
+}
+
+// Scoop returns the outs winning or tying both the hi and lo.
+func (o *Outs) Scoop() []Out {
+	return o.filter(func(out Out) bool {
+		return out.Scoop()
+	})
+}
+
+// Hi returns the outs winning or tying only the hi.
+func (o *Outs) Hi() []Out {
+	return o.filter(func(out Out) bool {
+		return out.Hi != OutNone && out.Lo == OutNone
+	})
+}
+
+// Lo returns the outs winning or tying only the lo.
+func (o *Outs) Lo() []Out {
+	return o.filter(func(out Out) bool {
+		return out.Hi == OutNone && out.Lo != OutNone
+	})
+}
+
+// ByFixed returns the outs grouped by the pocket's fixed hi rank after the
+// out is dealt.
+func (o *Outs) ByFixed() map[HandRank][]Out {
+	m := make(map[HandRank][]Out)
+	for _, out := range o.Outs {
+		m[out.Fixed] = append(m[out.Fixed], out)
+	}
+	return m
+}
+
+// filter returns the outs matching f.
+func (o *Outs) filter(f func(Out) bool) []Out {
+	var v []Out
+	for _, out := range o.Outs {
+		if f(out) {
+			v = append(v, out)
+		}
+	}
+	return v
+}
+
+// Outs calculates the outs for the pocket, by dealing each unseen card from
+// the type's deck as the next board card, after removing the pocket, opponent
+// pockets, board, and dead cards from the deck.
+//
+// With opponents, an out is a card that wins or ties the hi or lo against all
+// of the opponents' pockets. Without opponents, an out is a card that improves
+// the pocket's fixed hi rank (see HandRank.Fixed), or, for Low types, makes a
+// qualifying lo when the pocket does not have one.
+//
+// The board must contain at least the first street's board cards, and must not
+// be complete. The pocket and opponent pockets must contain the type's pocket
+// cards dealt for the board. Returns ErrInvalidType for types without a board,
+// or for Double types.
+func (typ Type) Outs(pocket []Card, opponents [][]Card, board, dead []Card) (*Outs, error) {
+	desc, ok := descs[typ]
+	if !ok || desc.Double {
+		return nil, ErrInvalidType
+	}
+	first, total := 0, 0
+	for _, street := range desc.Streets {
+		if first == 0 {
+			first = street.Board
+		}
+		total += street.Board
+	}
+	switch {
+	case total == 0:
+		return nil, ErrInvalidType
+	case len(board) < first, total <= len(board):
+		return nil, ErrInvalidBoard
+	}
+	// pocket count for the streets dealt through the board
+	count, n := 0, 0
+	for _, street := range desc.Streets {
+		if n += street.Board; len(board) < n {
+			break
+		}
+		count += street.Pocket - street.Discard
+	}
+	if len(pocket) != count {
+		return nil, ErrInvalidPocket
+	}
+	known := append(append([]Card(nil), pocket...), board...)
+	pockets := [][]Card{pocket}
+	for _, v := range opponents {
+		if len(v) != count {
+			return nil, ErrInvalidPocket
+		}
+		known, pockets = append(known, v...), append(pockets, v)
+	}
+	d, err := desc.Deck.Without(append(known, dead...)...)
+	if err != nil {
+		return nil, err
+	}
+	cur := typ.RankHand(pocket, board)
+	o := &Outs{
+		Type: typ,
+	}
+	v := make([]Card, len(board)+1)
+	copy(v, board)
+	for _, c := range d.All() {
+		v[len(board)] = c
+		o.Count++
+		hands := typ.RankHands(pockets, v)
+		out := Out{
+			Card:  c,
+			Fixed: hands[0].Fixed(),
+		}
+		switch {
+		case len(opponents) == 0:
+			if out.Fixed < cur.Fixed() {
+				out.Hi = OutWin
+			}
+			if desc.Low && !cur.LowValid() && hands[0].LowValid() {
+				out.Lo = OutWin
+			}
+		default:
+			win := NewWin(hands, nil, desc.Low)
+			out.Hi = outResult(win.Hi, win.HiPivot)
+			if desc.Low {
+				out.Lo = outResult(win.Lo, win.LoPivot)
+			}
+		}
+		if out.Hi != OutNone || out.Lo != OutNone {
+			o.Outs = append(o.Outs, out)
+		}
+	}
+	return o, nil
+}
+
+// Outs calculates the outs for the pocket against the opponent pockets, after
+// removing the publicly known dead cards (see Dealer.Dead) from the deck. The
+// opponents should not include folded players. See Type.Outs.
+func (d *Dealer) Outs(pocket []Card, opponents [][]Card, board []Card) (*Outs, error) {
+	return d.Type.Outs(pocket, opponents, board, d.Dead())
+}
+
+// outResult returns the result of the first pocket for the ordered hands.
+func outResult(order []int, pivot int) OutResult {
+	for i := 0; i < pivot; i++ {
+		if order[i] == 0 {
+			if pivot == 1 {
+				return OutWin
+			}
+			return OutTie
+		}
+	}
+	return OutNone
+}
@@ -0,0 +1,172 @@
+package cardrank
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestOuts(t *testing.T) {
+	tests := []struct {
+		pocket    string
+		opponents []string
+		board     string
+		dead      string
+		count     int
+		exp       map[HandRank]string
+	}{
+		{
+			"Ah Kh", []string{"Qs Qc"}, "2h 7h 9c", "", 45,
+			map[HandRank]string{
+				Flush: "3h 4h 5h 6h 8h 9h Th Jh Qh",
+				Pair:  "Ks As Kd Ad Kc Ac",
+			},
+		},
+		{
+			"Ah Kh", []string{"Qs Qc"}, "2h 7h 9c", "3h 4h As", 42,
+			map[HandRank]string{
+				Flush: "5h 6h 8h 9h Th Jh Qh",
+				Pair:  "Ks Kd Ad Kc Ac",
+			},
+		},
+		{
+			"Ah Kh", []string{"Qs Qc", "Ts Js"}, "2h 7h 9c", "", 43,
+			map[HandRank]string{
+				Flush: "3h 4h 5h 6h 8h 9h Th Jh Qh",
+				Pair:  "Ks As Kd Ad Kc Ac",
+			},
+		},
+		{
+			"Ah Kh", nil, "2h 7h 9c Ts", "", 46,
+			map[HandRank]string{
+				Flush: "3h 4h 5h 6h 8h 9h Th Jh Qh",
+				Pair:  "2s 7s 9s Ks As 2d 7d 9d Td Kd Ad 2c 7c Tc Kc Ac",
+			},
+		},
+	}
+	for i, test := range tests {
+		var opponents [][]Card
+		for _, s := range test.opponents {
+			opponents = append(opponents, Must(s))
+		}
+		o, err := Holdem.Outs(Must(test.pocket), opponents, Must(test.board), Must(test.dead))
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if o.Count != test.count {
+			t.Errorf("test %d expected count %d, got: %d", i, test.count, o.Count)
+		}
+		m, n := o.ByFixed(), 0
+		for r, exp := range test.exp {
+			v := make([]Card, len(m[r]))
+			for j, out := range m[r] {
+				if out.Hi != OutWin || out.Lo != OutNone {
+					t.Errorf("test %d expected %s win, got: %s %s", i, out.Card, out.Hi, out.Lo)
+				}
+				v[j] = out.Card
+			}
+			if s := fmt.Sprintf("%s", CardFormatter(v)); s != "["+exp+"]" {
+				t.Errorf("test %d expected %s outs %s, got: %s", i, r, exp, s)
+			}
+			n += len(v)
+		}
+		if len(o.Outs) != n {
+			t.Errorf("test %d expected %d outs, got: %d", i, n, len(o.Outs))
+		}
+	}
+}
+
+func TestOutsHiLo(t *testing.T) {
+	o, err := OmahaHiLo.Outs(Must("Ah 2h Kc Qc"), [][]Card{Must("As 3s Kd Td")}, Must("4d 5c 9s Ks"), nil)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if o.Count != 40 {
+		t.Errorf("expected count %d, got: %d", 40, o.Count)
+	}
+	for _, test := range []struct {
+		v   []Out
+		exp string
+	}{
+		{o.Scoop(), "[3h 6h 7h 8h 3d 6d 7d 8d 3c 6c 7c 8c]"},
+		{o.Hi(), "[4h 5h 9h Jh Qh Kh 5d 9d Jd Qd Ad 4c 9c Jc Ac]"},
+		{o.Lo(), "[6s 7s 8s]"},
+	} {
+		v := make([]Card, len(test.v))
+		for i, out := range test.v {
+			v[i] = out.Card
+		}
+		if s := fmt.Sprintf("%s", CardFormatter(v)); s != test.exp {
+			t.Errorf("expected %s, got: %s", test.exp, s)
+		}
+	}
+	// 3 makes the wheel, scooping
+	for _, out := range o.Scoop() {
+		if out.Card.Rank() == Three && (out.Fixed != Straight || out.Hi != OutWin || out.Lo != OutWin) {
+			t.Errorf("expected %s straight scoop, got: %s %s %s", out.Card, out.Fixed, out.Hi, out.Lo)
+		}
+	}
+}
+
+func TestOutsErrors(t *testing.T) {
+	tests := []struct {
+		typ       Type
+		pocket    string
+		opponents []string
+		board     string
+		err       error
+	}{
+		{Holdem, "Ah Kh", nil, "2h 7h", ErrInvalidBoard},
+		{Holdem, "Ah Kh", nil, "2h 7h 9c Td 3s", ErrInvalidBoard},
+		{Stud, "Ah Kh", nil, "", ErrInvalidType},
+		{Double, "Ah Kh", nil, "2h 7h 9c 2c 7c 9d", ErrInvalidType},
+		{Type(0), "Ah Kh", nil, "2h 7h 9c", ErrInvalidType},
+		{Holdem, "", nil, "2h 7h 9c", ErrInvalidPocket},
+		{Holdem, "Ah Kh Qh", nil, "2h 7h 9c", ErrInvalidPocket},
+		{Holdem, "Ah Kh", []string{"Qc"}, "2h 7h 9c", ErrInvalidPocket},
+		{Omaha, "Ah Kh", nil, "2h 7h 9c", ErrInvalidPocket},
+		{Omaha, "Ah Kh Qh Jh", []string{"Qc Qd"}, "2h 7h 9c", ErrInvalidPocket},
+		{Holdem, "Ah Kh", []string{"Ah Qh"}, "2h 7h 9c", ErrDuplicateCard},
+	}
+	for i, test := range tests {
+		var opponents [][]Card
+		for _, s := range test.opponents {
+			opponents = append(opponents, Must(s))
+		}
+		if _, err := test.typ.Outs(Must(test.pocket), opponents, Must(test.board), nil); !errors.Is(err, test.err) {
+			t.Errorf("test %d expected error %v, got: %v", i, test.err, err)
+		}
+	}
+}
+
+func TestDealerOuts(t *testing.T) {
+	d := Showtime.Dealer(rand.New(rand.NewSource(0)), 1)
+	d.Next()
+	pockets := [][]Card{
+		Must("Ah Kh"),
+		Must("Qh Qs"),
+		Must("Jh Tc"),
+	}
+	board := Must("2h 7h 9c")
+	if err := d.Fold(2, pockets[2]); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	o, err := d.Outs(pockets[0], pockets[1:2], board)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	exp, err := Showtime.Outs(pockets[0], pockets[1:2], board, pockets[2])
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if o.Count != exp.Count || o.Count != 52-7-2 {
+		t.Errorf("expected count %d, got: %d", exp.Count, o.Count)
+	}
+	if s, exp := fmt.Sprintf("%v", CardFormatter(o.Cards())), fmt.Sprintf("%v", CardFormatter(exp.Cards())); s != exp {
+		t.Errorf("expected %s, got: %s", exp, s)
+	}
+	if !hasCard(Must("3h")[0], o.Cards()...) || hasCard(Must("Jh")[0], o.Cards()...) {
+		t.Errorf("expected 3h and not the dead Jh to be an out, got: %v", o.Cards())
+	}
+}
@@ -0,0 +1,594 @@
+package cardrank
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Range is a weighted range of pockets.
+//
+// See ParseRange for an overview of range notation.
+type Range struct {
+	n      int
+	combos map[rangeKey]float64
+	pats   []string
+}
+
+// Combo is a weighted pocket.
+type Combo struct {
+	Pocket []Card
+	Weight float64
+}
+
+// rangeKey is a range pocket key.
+type rangeKey [6]Card
+
+// rangeMaxPockets is the maximum count of pockets matched by a range pattern,
+// before the suit constraint.
+const rangeMaxPockets = 1 << 19
+
+// ParseRange parses a range of comma separated pockets from s.
+//
+// Holdem pockets are described as pairs (TT), suited (AKs), offsuit (AKo), or
+// both suited and offsuit (AK) hands. A '+' suffix adds all better pairs (TT+)
+// or all higher kickers (ATs+), and two hands separated by a '-' add all the
+// pairs (TT-77) or kickers (A5s-A2s) between them.
+//
+// Omaha pockets are described as patterns of 4, 5, or 6 ranks, where '*'
+// matches any card, followed by an optional suit constraint: ds (double
+// suited), ss (single suited), or r (rainbow) (ex: AA**ds, KQJTss, AKQ*).
+// Patterns matching more than 524,288 pockets, before the suit constraint, are
+// not supported (ex: *****).
+//
+// Pockets can also be described as explicit cards (ex: AhKh, AsAhKsQh).
+// Pockets can be followed by a ':' and a weight between 0 and 1 (ex:
+// KQo:0.5). Later pockets replace the weight of earlier pockets, and a weight
+// of 0 removes the pockets from the range.
+func ParseRange(s string) (*Range, error) {
+	r := &Range{
+		combos: make(map[rangeKey]float64),
+	}
+	if strings.TrimSpace(s) == "" {
+		return r, nil
+	}
+	for i, tok := range strings.Split(s, ",") {
+		if err := r.add(strings.TrimSpace(tok)); err != nil {
+			return nil, &ParseError{
+				S:   tok,
+				N:   i,
+				Err: err,
+			}
+		}
+	}
+	return r, nil
+}
+
+// MustRange creates a range from s.
+//
+// See ParseRange for an overview of range notation.
+func MustRange(s string) *Range {
+	r, err := ParseRange(s)
+	if err == nil {
+		return r
+	}
+	panic(err)
+}
+
+// add adds the pockets described by tok to the range.
+func (r *Range) add(tok string) error {
+	w := 1.0
+	if i := strings.LastIndexByte(tok, ':'); i != -1 {
+		f, err := strconv.ParseFloat(tok[i+1:], 64)
+		if err != nil || math.IsNaN(f) || f < 0 || 1 < f {
+			return ErrInvalidRange
+		}
+		tok, w = tok[:i], f
+	}
+	pockets, pat, ok := parseRangeHands(tok)
+	if !ok {
+		pockets, pat, ok = parseRangePattern(tok)
+	}
+	if !ok {
+		v, err := Parse(tok)
+		if err != nil || (len(v) != 2 && (len(v) < 4 || 6 < len(v))) || hasDuplicate(v) {
+			return ErrInvalidRange
+		}
+		k := newRangeKey(v)
+		pockets = [][]Card{k[:len(v)]}
+	}
+	if len(pockets) == 0 {
+		return ErrInvalidRange
+	}
+	n := len(pockets[0])
+	if r.n != 0 && r.n != n {
+		return ErrInvalidRange
+	}
+	r.n = n
+	for _, v := range pockets {
+		k := newRangeKey(v)
+		if w == 0 {
+			delete(r.combos, k)
+		} else {
+			r.combos[k] = w
+		}
+	}
+	if n != 2 {
+		if pat == "" {
+			pat = formatPocket(pockets[0])
+		}
+		if w != 1 {
+			pat += ":" + strconv.FormatFloat(w, 'f', -1, 64)
+		}
+		r.addPattern(pat)
+	}
+	return nil
+}
+
+// addPattern adds the formatted pattern, removing any earlier matching
+// pattern.
+func (r *Range) addPattern(pat string) {
+	base := func(s string) string {
+		if i := strings.IndexByte(s, ':'); i != -1 {
+			return s[:i]
+		}
+		return s
+	}
+	var v []string
+	for _, p := range r.pats {
+		if base(p) != base(pat) {
+			v = append(v, p)
+		}
+	}
+	r.pats = append(v, pat)
+}
+
+// Pocket returns the count of cards in the range's pockets, or 0 when the range
+// is empty.
+func (r *Range) Pocket() int {
+	return r.n
+}
+
+// Len returns the count of pockets in the range.
+func (r *Range) Len() int {
+	return len(r.combos)
+}
+
+// Combos returns the range's weighted pockets, excluding pockets containing any
+// of the dead cards. Pockets are ordered by rank, high to low.
+func (r *Range) Combos(dead ...Card) []Combo {
+	m := make(map[Card]bool, len(dead))
+	for _, c := range dead {
+		m[c] = true
+	}
+	var v []Combo
+loop:
+	for _, k := range r.keys() {
+		pocket := make([]Card, r.n)
+		copy(pocket, k[:r.n])
+		for _, c := range pocket {
+			if m[c] {
+				continue loop
+			}
+		}
+		v = append(v, Combo{
+			Pocket: pocket,
+			Weight: r.combos[k],
+		})
+	}
+	return v
+}
+
+// keys returns the range's pocket keys, in order.
+func (r *Range) keys() []rangeKey {
+	keys := make([]rangeKey, 0, len(r.combos))
+	for k := range r.combos {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		for k := 0; k < r.n; k++ {
+			if a, b := keys[i][k], keys[j][k]; a != b {
+				return pocketLess(a, b)
+			}
+		}
+		return false
+	})
+	return keys
+}
+
+// String satisfies the fmt.Stringer interface.
+//
+// Holdem ranges are formatted in their compact canonical form (ex: TT+, AKs,
+// A5s-A2s, KQo:0.5). Omaha ranges are formatted as their normalized patterns.
+func (r *Range) String() string {
+	if r.n != 2 {
+		return strings.Join(r.pats, ", ")
+	}
+	var v []string
+	seen := make(map[rangeKey]bool)
+	full := func(keys []rangeKey) (float64, bool) {
+		w, ok := r.combos[keys[0]]
+		for _, k := range keys[1:] {
+			ok = ok && r.combos[k] == w
+		}
+		if ok {
+			for _, k := range keys {
+				seen[k] = true
+			}
+		}
+		return w, ok
+	}
+	// pairs
+	var pairs []rangeRun
+	for rank := Ace; rank != InvalidRank; rank-- {
+		if w, ok := full(rangeHand{rank, rank, 0}.keys()); ok {
+			pairs = appendRun(pairs, rank, w)
+		}
+	}
+	for _, run := range pairs {
+		v = append(v, run.format(rangeHand{run.hi, run.hi, 0}, Ace))
+	}
+	// suited and offsuit
+	for hi := Ace; Three <= hi && hi != InvalidRank; hi-- {
+		runs := make(map[byte][]rangeRun)
+		for lo := hi - 1; lo != InvalidRank; lo-- {
+			s, o := rangeHand{hi, lo, 's'}.keys(), rangeHand{hi, lo, 'o'}.keys()
+			sw, sok := r.combos[s[0]], true
+			for _, k := range s[1:] {
+				sok = sok && r.combos[k] == sw
+			}
+			ow, ook := r.combos[o[0]], true
+			for _, k := range o[1:] {
+				ook = ook && r.combos[k] == ow
+			}
+			sok, ook = sok && sw != 0, ook && ow != 0
+			switch {
+			case sok && ook && sw == ow:
+				full(append(s, o...))
+				runs[0] = appendRun(runs[0], lo, sw)
+			default:
+				if sok {
+					full(s)
+					runs['s'] = appendRun(runs['s'], lo, sw)
+				}
+				if ook {
+					full(o)
+					runs['o'] = appendRun(runs['o'], lo, ow)
+				}
+			}
+		}
+		for _, kind := range []byte{0, 's', 'o'} {
+			for _, run := range runs[kind] {
+				v = append(v, run.format(rangeHand{hi, run.hi, kind}, hi-1))
+			}
+		}
+	}
+	// remaining explicit pockets
+	for _, k := range r.keys() {
+		if !seen[k] {
+			v = append(v, formatWeight(formatPocket(k[:2]), r.combos[k]))
+		}
+	}
+	return strings.Join(v, ", ")
+}
+
+// MarshalText satisfies the encoding.TextMarshaler interface.
+func (r *Range) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText satisfies the encoding.TextUnmarshaler interface.
+func (r *Range) UnmarshalText(buf []byte) error {
+	v, err := ParseRange(string(buf))
+	if err != nil {
+		return err
+	}
+	*r = *v
+	return nil
+}
+
+// rangeHand is a Holdem starting hand (ie, TT, AKs, AKo, or AK).
+type rangeHand struct {
+	hi, lo Rank
+	kind   byte
+}
+
+// parseRangeHand parses a Holdem starting hand.
+func parseRangeHand(s string) (rangeHand, bool) {
+	v := []rune(s)
+	if len(v) < 2 || 3 < len(v) {
+		return rangeHand{}, false
+	}
+	hi, lo := RankFromRune(v[0]), RankFromRune(v[1])
+	if hi == InvalidRank || lo == InvalidRank {
+		return rangeHand{}, false
+	}
+	if hi < lo {
+		hi, lo = lo, hi
+	}
+	h := rangeHand{hi: hi, lo: lo}
+	if len(v) == 3 {
+		switch v[2] {
+		case 's', 'S':
+			h.kind = 's'
+		case 'o', 'O':
+			h.kind = 'o'
+		default:
+			return rangeHand{}, false
+		}
+		if hi == lo {
+			return rangeHand{}, false
+		}
+	}
+	return h, true
+}
+
+// parseRangeHands parses a Holdem starting hand, with an optional '+' suffix,
+// or a '-' separated span of starting hands, returning the pockets.
+func parseRangeHands(s string) ([][]Card, string, bool) {
+	var hands []rangeHand
+	switch i := strings.IndexByte(s, '-'); {
+	case strings.HasSuffix(s, "+"):
+		h, ok := parseRangeHand(s[:len(s)-1])
+		if !ok {
+			return nil, "", false
+		}
+		switch {
+		case h.hi == h.lo:
+			for r := h.hi; r <= Ace; r++ {
+				hands = append(hands, rangeHand{r, r, 0})
+			}
+		default:
+			for r := h.lo; r < h.hi; r++ {
+				hands = append(hands, rangeHand{h.hi, r, h.kind})
+			}
+		}
+	case i != -1:
+		a, ok1 := parseRangeHand(s[:i])
+		b, ok2 := parseRangeHand(s[i+1:])
+		switch {
+		case !ok1 || !ok2 || a.kind != b.kind:
+			return nil, "", false
+		case a.hi == a.lo && b.hi == b.lo:
+			lo, hi := min(a.hi, b.hi), a.hi+b.hi-min(a.hi, b.hi)
+			for r := lo; r <= hi; r++ {
+				hands = append(hands, rangeHand{r, r, 0})
+			}
+		case a.hi == b.hi && a.hi != a.lo && b.hi != b.lo:
+			lo, hi := min(a.lo, b.lo), a.lo+b.lo-min(a.lo, b.lo)
+			for r := lo; r <= hi; r++ {
+				hands = append(hands, rangeHand{a.hi, r, a.kind})
+			}
+		default:
+			return nil, "", false
+		}
+	default:
+		h, ok := parseRangeHand(s)
+		if !ok {
+			return nil, "", false
+		}
+		hands = append(hands, h)
+	}
+	var pockets [][]Card
+	for _, h := range hands {
+		for _, k := range h.keys() {
+			pockets = append(pockets, []Card{k[0], k[1]})
+		}
+	}
+	return pockets, "", true
+}
+
+// keys returns the pocket keys for the hand.
+func (h rangeHand) keys() []rangeKey {
+	var keys []rangeKey
+	suits := []Suit{Spade, Heart, Diamond, Club}
+	for i, s := range suits {
+		for j, t := range suits {
+			switch {
+			case h.hi == h.lo && j <= i,
+				h.hi != h.lo && h.kind == 's' && s != t,
+				h.hi != h.lo && h.kind == 'o' && s == t:
+				continue
+			}
+			keys = append(keys, newRangeKey([]Card{New(h.hi, s), New(h.lo, t)}))
+		}
+	}
+	return keys
+}
+
+// rangeRun is a run of consecutive ranks having the same weight.
+type rangeRun struct {
+	hi, lo Rank
+	w      float64
+}
+
+// appendRun appends the rank to the last run when consecutive and having the
+// same weight, otherwise appends a new run.
+func appendRun(runs []rangeRun, r Rank, w float64) []rangeRun {
+	if n := len(runs); n != 0 && runs[n-1].lo == r+1 && runs[n-1].w == w {
+		runs[n-1].lo = r
+		return runs
+	}
+	return append(runs, rangeRun{r, r, w})
+}
+
+// format formats the run, with h as the run's high hand and top as the highest
+// possible rank of the run.
+func (run rangeRun) format(h rangeHand, top Rank) string {
+	lo := h
+	if h.hi == h.lo {
+		lo.hi, lo.lo = run.lo, run.lo
+	} else {
+		lo.lo = run.lo
+	}
+	var s string
+	switch {
+	case run.hi == run.lo:
+		s = h.String()
+	case run.hi == top:
+		s = lo.String() + "+"
+	default:
+		s = h.String() + "-" + lo.String()
+	}
+	return formatWeight(s, run.w)
+}
+
+// String satisfies the fmt.Stringer interface.
+func (h rangeHand) String() string {
+	s := string(h.hi.Byte()) + string(h.lo.Byte())
+	if h.kind != 0 {
+		s += string(h.kind)
+	}
+	return s
+}
+
+// parseRangePattern parses a Omaha range pattern, returning the matching
+// pockets and the normalized pattern.
+func parseRangePattern(s string) ([][]Card, string, bool) {
+	var ranks []Rank
+	i := 0
+	for v := []rune(s); i < len(v); i++ {
+		r := RankFromRune(v[i])
+		switch {
+		case v[i] == '*':
+			r = InvalidRank
+		case r == InvalidRank:
+			goto suit
+		}
+		ranks = append(ranks, r)
+	}
+suit:
+	suit := strings.ToLower(string([]rune(s)[i:]))
+	switch {
+	case len(ranks) < 4 || 6 < len(ranks),
+		suit != "" && suit != "ds" && suit != "ss" && suit != "r":
+		return nil, "", false
+	}
+	// order ranks high to low, wildcards last
+	sort.Slice(ranks, func(i, j int) bool {
+		if ranks[i] == InvalidRank || ranks[j] == InvalidRank {
+			return ranks[j] == InvalidRank && ranks[i] != InvalidRank
+		}
+		return ranks[i] > ranks[j]
+	})
+	// count the pockets before enumerating
+	var counts [13]int
+	wild := 0
+	for _, r := range ranks {
+		if r == InvalidRank {
+			wild++
+		} else {
+			counts[r]++
+		}
+	}
+	n := binomial(52-len(ranks)+wild, wild)
+	for _, count := range counts {
+		n *= binomial(4, count)
+	}
+	if rangeMaxPockets < n {
+		return nil, "", false
+	}
+	pat := make([]byte, len(ranks))
+	for i, r := range ranks {
+		if pat[i] = r.Byte(); r == InvalidRank {
+			pat[i] = '*'
+		}
+	}
+	m := make(map[rangeKey]bool)
+	var pockets [][]Card
+	v := make([]Card, len(ranks))
+	var f func(int, int)
+	f = func(i, j int) {
+		if i == len(ranks) {
+			if suitPattern(v, suit) {
+				if k := newRangeKey(v); !m[k] {
+					m[k] = true
+					pockets = append(pockets, k[:len(v)])
+				}
+			}
+			return
+		}
+		// start after the previous card of the same rank (or wildcard)
+		if i == 0 || ranks[i] != ranks[i-1] {
+			j = 0
+		}
+		for ; j < len(unshuffledFrench); j++ {
+			c := unshuffledFrench[j]
+			if (ranks[i] == InvalidRank || c.Rank() == ranks[i]) && !hasCard(c, v[:i]...) {
+				v[i] = c
+				f(i+1, j+1)
+			}
+		}
+	}
+	f(0, 0)
+	return pockets, string(pat) + suit, true
+}
+
+// suitPattern returns true when the pocket matches the suit constraint.
+func suitPattern(v []Card, suit string) bool {
+	var counts [4]int
+	for _, c := range v {
+		counts[c.SuitIndex()]++
+	}
+	n := 0
+	for _, count := range counts {
+		if 2 <= count {
+			n++
+		}
+	}
+	switch suit {
+	case "ds":
+		return n == 2
+	case "ss":
+		return n == 1
+	case "r":
+		return n == 0
+	}
+	return true
+}
+
+// newRangeKey creates a range key for the pocket, ordering the pocket's cards.
+func newRangeKey(pocket []Card) rangeKey {
+	var k rangeKey
+	copy(k[:], pocket)
+	sort.Slice(k[:len(pocket)], func(i, j int) bool {
+		return pocketLess(k[i], k[j])
+	})
+	return k
+}
+
+// pocketLess orders cards by rank, high to low, and then by suit.
+func pocketLess(a, b Card) bool {
+	if m, n := a.Rank(), b.Rank(); m != n {
+		return m > n
+	}
+	return a.Suit() < b.Suit()
+}
+
+// formatPocket formats the pocket's cards.
+func formatPocket(pocket []Card) string {
+	var sb strings.Builder
+	for _, c := range pocket {
+		sb.WriteString(c.String())
+	}
+	return sb.String()
+}
+
+// formatWeight formats s with the weight.
+func formatWeight(s string, w float64) string {
+	if w == 1 {
+		return s
+	}
+	return s + ":" + strconv.FormatFloat(w, 'f', -1, 64)
+}
+
+// hasDuplicate returns true when v contains a duplicate card.
+func hasDuplicate(v []Card) bool {
+	for i, c := range v {
+		if hasCard(c, v[i+1:]...) {
+			return true
+		}
+	}
+	return false
+}
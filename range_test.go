@@ -0,0 +1,117 @@
+package cardrank
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		s   string
+		n   int
+		exp string
+	}{
+		{"", 0, ""},
+		{"AA", 6, "AA"},
+		{"AKs", 4, "AKs"},
+		{"KAo", 12, "AKo"},
+		{"AK", 16, "AK"},
+		{"AKs, AKo", 16, "AK"},
+		{"TT+", 30, "TT+"},
+		{"TT-77, 88", 24, "TT-77"},
+		{"77-TT", 24, "TT-77"},
+		{"A5s-A2s", 16, "A5s-A2s"},
+		{"ATs+", 16, "ATs+"},
+		{"KQo:0.5", 12, "KQo:0.5"},
+		{"AKs, TT+, A5s-A2s, KQo:0.5", 62, "TT+, AKs, A5s-A2s, KQo:0.5"},
+		{"22+, A2s+, KTo+", 6*13 + 4*12 + 12*3, "22+, A2s+, KTo+"},
+		{"AK, AKs:0.25", 16, "AKs:0.25, AKo"},
+		{"AK, AhKh:0", 15, "AKo, AsKs, AdKd, AcKc"},
+		{"AhKh, KhAh:0.75", 1, "AhKh:0.75"},
+		{"JJ+:0.5, QQ", 24, "KK+:0.5, QQ, JJ:0.5"},
+		{"AA**ds", 6 * 12 * 12, "AA**ds"},
+		{"AhAsKhKs", 1, "AsAhKsKh"},
+		{"kqjt, KQJT:0.5", 256, "KQJT:0.5"},
+		{"****r", 13 * 13 * 13 * 13, "****r"},
+	}
+	for i, test := range tests {
+		r, err := ParseRange(test.s)
+		if err != nil {
+			t.Fatalf("test %d %q expected no error, got: %v", i, test.s, err)
+		}
+		if n := r.Len(); n != test.n {
+			t.Errorf("test %d %q expected %d combos, got: %d", i, test.s, test.n, n)
+		}
+		s := r.String()
+		if s != test.exp {
+			t.Errorf("test %d %q expected %q, got: %q", i, test.s, test.exp, s)
+		}
+		// round trip
+		if v := MustRange(s).String(); v != s {
+			t.Errorf("test %d %q expected %q, got: %q", i, test.s, s, v)
+		}
+	}
+}
+
+func TestParseRangeErrors(t *testing.T) {
+	for i, s := range []string{
+		"AAs",
+		"AKx",
+		"AK,",
+		"AK:2",
+		"AK:-1",
+		"AK:x",
+		"AK-QJ",
+		"AKs-AJo",
+		"TT-AK",
+		"AKQ",
+		"AKQJds2",
+		"AKQJxx",
+		"AhAh",
+		"AhKhQh",
+		"AK, AAKK",
+		"AA**ds, AsKs",
+		"*****",
+		"******",
+		"AA****",
+	} {
+		_, err := ParseRange(s)
+		switch {
+		case err == nil:
+			t.Errorf("test %d %q expected error", i, s)
+		case !errors.Is(err, ErrInvalidRange):
+			t.Errorf("test %d %q expected error %v, got: %v", i, s, ErrInvalidRange, err)
+		}
+	}
+}
+
+func TestRangeCombos(t *testing.T) {
+	r := MustRange("AK, QQ:0.5")
+	v := r.Combos(Must("As Qh")...)
+	if n, exp := len(v), 12+3; n != exp {
+		t.Fatalf("expected %d combos, got: %d", exp, n)
+	}
+	for _, combo := range v {
+		for _, c := range combo.Pocket {
+			if c == Must("As")[0] || c == Must("Qh")[0] {
+				t.Errorf("expected no dead cards, got: %v", combo.Pocket)
+			}
+		}
+		exp := 1.0
+		if combo.Pocket[0].Rank() == Queen {
+			exp = 0.5
+		}
+		if combo.Weight != exp {
+			t.Errorf("expected %v weight %f, got: %f", combo.Pocket, exp, combo.Weight)
+		}
+	}
+	if s, exp := fmt.Sprintf("%v", CardFormatter(v[0].Pocket)), "[Ah Ks]"; s != exp {
+		t.Errorf("expected %s, got: %s", exp, s)
+	}
+	for i, combo := range MustRange("AA**ds").Combos() {
+		if !suitPattern(combo.Pocket, "ds") || combo.Pocket[0].Rank() != Ace || combo.Pocket[1].Rank() != Ace {
+			t.Errorf("test %d expected AA**ds, got: %v", i, combo.Pocket)
+		}
+	}
+}
@@ -33,6 +33,22 @@ const (
 	Lowball        Type = 'L'<<8 | '1' // L1
 	LowballTriple  Type = 'L'<<8 | '3' // L3
 	Soko           Type = 'K'<<8 | 'o' // Ko
+	AceSix         Type = 'A'<<8 | '1' // A1
+	AceSixTriple   Type = 'A'<<8 | '3' // A3
+	London         Type = 'R'<<8 | 'l' // Rl
+	Badeucy        Type = 'B'<<8 | 'd' // Bd
+	Badacey        Type = 'B'<<8 | 'c' // Bc
+	Dramaha        Type = 'D'<<8 | 'h' // Dh
+	DramahaDeuce   Type = 'D'<<8 | '2' // D2
+	Dramadugi      Type = 'D'<<8 | 'b' // Db
+	Pineapple      Type = 'H'<<8 | 'p' // Hp
+	CrazyPineapple Type = 'H'<<8 | 'c' // Hc
+	LazyPineapple  Type = 'H'<<8 | 'l' // Hl
+	Irish          Type = 'H'<<8 | 'i' // Hi
+	DeucesWild     Type = 'W'<<8 | '2' // W2
+	JokersWild     Type = 'W'<<8 | 'j' // Wj
+	DrawBug        Type = 'W'<<8 | 'b' // Wb
+	California     Type = 'W'<<8 | 'c' // Wc
 )
 
 // DefaultTypes returns the default type descriptions.
@@ -66,6 +82,22 @@ func DefaultTypes() []TypeDesc {
 		{"L1", Lowball, "Lowball", WithLowball(false)},
 		{"L3", LowballTriple, "LowballTriple", WithLowball(true)},
 		{"Ko", Soko, "Soko", WithSoko()},
+		{"A1", AceSix, "AceSix", WithAceSix(false)},
+		{"A3", AceSixTriple, "AceSixTriple", WithAceSix(true)},
+		{"Rl", London, "London", WithLondon()},
+		{"Bd", Badeucy, "Badeucy", WithBadeucy()},
+		{"Bc", Badacey, "Badacey", WithBadacey()},
+		{"Dh", Dramaha, "Dramaha", WithDramaha(EvalDramaha)},
+		{"D2", DramahaDeuce, "DramahaDeuce", WithDramaha(EvalDramahaDeuce)},
+		{"Db", Dramadugi, "Dramadugi", WithDramaha(EvalDramadugi)},
+		{"Hp", Pineapple, "Pineapple", WithPineapple(0)},
+		{"Hc", CrazyPineapple, "CrazyPineapple", WithPineapple(1)},
+		{"Hl", LazyPineapple, "LazyPineapple", WithPineapple(3)},
+		{"Hi", Irish, "Irish", WithIrish()},
+		{"W2", DeucesWild, "DeucesWild", WithDeucesWild()},
+		{"Wj", JokersWild, "JokersWild", WithJokersWild()},
+		{"Wb", DrawBug, "DrawBug", WithDrawBug()},
+		{"Wc", California, "California", WithCalifornia()},
 	} {
 		desc, err := NewTypeDesc(v.id, v.typ, v.name, v.opt)
 		if err != nil {
@@ -228,7 +260,7 @@ func (typ Type) RankHands(pockets [][]Card, board []Card) []*Hand {
 
 // String satisfies the fmt.Stringer interface.
 func (typ Type) String() string {
-	return string([]byte{byte(typ >> 8 & 0xf), byte(typ & 0xf)})
+	return string([]byte{byte(typ >> 8), byte(typ)})
 }
 
 // Format satisfies the fmt.Formatter interface.
@@ -241,7 +273,7 @@ func (typ Type) Format(f fmt.State, verb rune) {
 	if desc, ok := descs[typ]; ok {
 		fmt.Fprint(f, desc.Name)
 	} else {
-		fmt.Fprintf(f, "Type(%d)", typ)
+		fmt.Fprintf(f, "Type(%d)", uint16(typ))
 	}
 }
 
@@ -259,8 +291,8 @@ func (typ *Type) UnmarshalText(buf []byte) error {
 			return nil
 		}
 	}
-	if len(name) == 2 {
-		if id, err := IdToType(name); err == nil {
+	if id, err := IdToType(string(buf)); err == nil {
+		if _, ok := descs[id]; ok {
 			*typ = id
 			return nil
 		}
@@ -274,7 +306,8 @@ var descs map[Type]TypeDesc = make(map[Type]TypeDesc)
 // evals are eval funcs.
 var evals map[Type]EvalFunc = make(map[Type]EvalFunc)
 
-// RegisterType registers a type.
+// RegisterType registers a type. Returns ErrInvalidDeck when the deck is not
+// registered, or when the deck has jokers and the eval type is not Wild.
 func RegisterType(desc TypeDesc) error {
 	if _, ok := descs[desc.Type]; ok {
 		return ErrInvalidId
@@ -286,6 +319,22 @@ func RegisterType(desc TypeDesc) error {
 			return fmt.Errorf("%s street %d id %c must be unique", desc.Type, i, street.Id)
 		}
 	}
+	// check deck
+	if _, ok := decks[desc.Deck]; !ok {
+		return fmt.Errorf("%s deck %d: %w", desc.Type, desc.Deck, ErrInvalidDeck)
+	}
+	// check jokers
+	if !desc.Eval.Wild() {
+		for _, c := range decks[desc.Deck].Cards {
+			if c.Joker() {
+				return fmt.Errorf("%s deck %d has jokers, eval %d is not wild: %w", desc.Type, desc.Deck, desc.Eval, ErrInvalidDeck)
+			}
+		}
+	}
+	// check max
+	if !desc.CanDeal(desc.Max) {
+		return fmt.Errorf("%s max %d: %w", desc.Type, desc.Max, ErrNotEnoughCards)
+	}
 	desc.Num = len(descs)
 	descs[desc.Type] = desc
 	evals[desc.Type] = desc.Eval.New(desc.Low)
@@ -324,7 +373,7 @@ type TypeDesc struct {
 	Blinds []string
 	// Streets are the betting streets.
 	Streets []StreetDesc
-	// Deck is the deck type.
+	// Deck is the deck type (see RegisterDeck).
 	Deck DeckType
 	// Eval is the eval type.
 	Eval EvalType
@@ -352,6 +401,44 @@ func NewTypeDesc(id string, typ Type, name string, opts ...TypeOption) (*TypeDes
 	return desc, nil
 }
 
+// CanDeal returns true when the type's deck has enough cards to deal all
+// streets to the number of hands. When the last street dealing pockets deals a
+// single pocket card, only a single community card is needed when the deck
+// runs out (see Dealer.Community). Does not consider draws.
+func (desc *TypeDesc) CanDeal(hands int) bool {
+	deck, ok := decks[desc.Deck]
+	return ok && 0 <= desc.remaining(len(deck.Cards), 0, hands)
+}
+
+// remaining returns the remaining count of n cards after dealing the streets
+// starting at street start to the number of hands, or -1 when there are not
+// enough cards.
+func (desc *TypeDesc) remaining(n, start, hands int) int {
+	for i := start; i < len(desc.Streets); i++ {
+		street := desc.Streets[i]
+		if 0 < street.Pocket {
+			switch m := street.PocketDiscard + street.Pocket*hands; {
+			case m <= n:
+				n -= m
+			case street.Pocket == 1 && lastPocket(desc.Streets, i):
+				n -= street.PocketDiscard + 1
+			default:
+				return -1
+			}
+		}
+		if 0 < street.Board {
+			n -= street.BoardDiscard + street.Board
+			if desc.Double {
+				n -= street.Board
+			}
+		}
+		if n < 0 {
+			return -1
+		}
+	}
+	return n
+}
+
 // Apply applies street options.
 func (desc *TypeDesc) Apply(opts ...StreetOption) {
 	for _, o := range opts {
@@ -456,6 +543,35 @@ func WithSwap(opts ...StreetOption) TypeOption {
 	}
 }
 
+// WithPineapple is a type description option to set Pineapple definitions.
+//
+// Same as Holdem, but with 3 pocket cards, with each player discarding 1
+// pocket card at the end of the street (0 for the pre-flop, 1 for the flop
+// (Crazy Pineapple), or 3 for the river (Lazy Pineapple)).
+func WithPineapple(street int, opts ...StreetOption) TypeOption {
+	return func(desc *TypeDesc) {
+		desc.Max = 10
+		desc.Blinds = HoldemBlinds()
+		desc.Streets = HoldemStreets(3, 1, 3, 1, 1)
+		desc.Streets[street].Discard = 1
+		desc.Apply(opts...)
+	}
+}
+
+// WithIrish is a type description option to set Irish definitions.
+//
+// Same as Holdem, but with 4 pocket cards, with each player discarding 2
+// pocket cards at the end of the flop.
+func WithIrish(opts ...StreetOption) TypeOption {
+	return func(desc *TypeDesc) {
+		desc.Max = 10
+		desc.Blinds = HoldemBlinds()
+		desc.Streets = HoldemStreets(4, 1, 3, 1, 1)
+		desc.Streets[1].Discard = 2
+		desc.Apply(opts...)
+	}
+}
+
 // WithOmaha is a type description option to set Omaha definitions.
 func WithOmaha(low bool, opts ...StreetOption) TypeOption {
 	return func(desc *TypeDesc) {
@@ -546,7 +662,7 @@ func WithFusion(low bool, opts ...StreetOption) TypeOption {
 // WithStud is a type description option to set Stud definitions.
 func WithStud(low bool, opts ...StreetOption) TypeOption {
 	return func(desc *TypeDesc) {
-		desc.Max = 7
+		desc.Max = 8
 		desc.Low = low
 		desc.Blinds = StudBlinds()
 		desc.Streets = StudStreets()
@@ -561,8 +677,8 @@ func WithStud(low bool, opts ...StreetOption) TypeOption {
 // Same as Stud, but with a Ace-to-Five low card ranking.
 func WithRazz(opts ...StreetOption) TypeOption {
 	return func(desc *TypeDesc) {
-		desc.Max = 7
-		desc.Blinds = HoldemBlinds()
+		desc.Max = 8
+		desc.Blinds = StudBlinds()
 		desc.Streets = StudStreets()
 		desc.Eval = EvalRazz
 		desc.Apply(opts...)
@@ -597,7 +713,7 @@ func WithLowball(multi bool, opts ...StreetOption) TypeOption {
 		desc.Eval = EvalLowball
 		desc.HiComp = CompLowball
 		for i := 1; i < 4; i++ {
-			desc.Streets[1].PocketDraw = 5
+			desc.Streets[i].PocketDraw = 5
 		}
 		desc.Apply(opts...)
 	}
@@ -615,6 +731,154 @@ func WithSoko(opts ...StreetOption) TypeOption {
 	}
 }
 
+// WithAceSix is a type description option to set Ace-to-Six Lowball
+// definitions.
+//
+// Same as Lowball, but with a Ace-to-Six low card ranking.
+func WithAceSix(multi bool, opts ...StreetOption) TypeOption {
+	return func(desc *TypeDesc) {
+		desc.Max = 8
+		desc.Once = !multi
+		desc.Streets = NumberedStreets(5, 0, 0, 0)
+		desc.Blinds = HoldemBlinds()
+		desc.Eval = EvalAceSix
+		desc.HiComp = CompHi
+		for i := 1; i < 4; i++ {
+			desc.Streets[i].PocketDraw = 5
+		}
+		desc.Apply(opts...)
+	}
+}
+
+// WithLondon is a type description option to set London (Ace-to-Six Razz)
+// definitions.
+//
+// Same as Razz, but with a Ace-to-Six low card ranking.
+func WithLondon(opts ...StreetOption) TypeOption {
+	return func(desc *TypeDesc) {
+		desc.Max = 8
+		desc.Blinds = StudBlinds()
+		desc.Streets = StudStreets()
+		desc.Eval = EvalAceSix
+		desc.HiComp = CompHi
+		desc.Apply(opts...)
+	}
+}
+
+// WithBadeucy is a type description option to set Badeucy definitions.
+//
+// 5 cards, split pot between the best Badugi hand (using 4 of the 5 cards)
+// and the best Two-to-Seven low hand
+// All 5 face down pre-flop
+// 3 rounds of player discards (up to 5)
+func WithBadeucy(opts ...StreetOption) TypeOption {
+	return func(desc *TypeDesc) {
+		desc.Max = 8
+		desc.Low = true
+		desc.Streets = NumberedStreets(5, 0, 0, 0)
+		desc.Blinds = HoldemBlinds()
+		desc.Eval = EvalBadeucy
+		desc.LoComp = CompLo
+		for i := 1; i < 4; i++ {
+			desc.Streets[i].PocketDraw = 5
+		}
+		desc.Apply(opts...)
+	}
+}
+
+// WithBadacey is a type description option to set Badacey definitions.
+//
+// Same as Badeucy, but with a Ace-to-Five low card ranking for the low hand.
+func WithBadacey(opts ...StreetOption) TypeOption {
+	return func(desc *TypeDesc) {
+		WithBadeucy(opts...)(desc)
+		desc.Eval = EvalBadacey
+	}
+}
+
+// WithDramaha is a type description option to set Dramaha definitions,
+// using the eval type to evaluate the hands.
+//
+// 5 cards, split pot between the best Omaha hand (using exactly 2 pocket and 3
+// board cards) and the best draw hand (using only the pocket), as determined
+// by the eval type (ie, high, Two-to-Seven low, or Badugi)
+// All 5 face down pre-flop
+// 1 round of player discards (up to 5) after the flop
+func WithDramaha(typ EvalType, opts ...StreetOption) TypeOption {
+	return func(desc *TypeDesc) {
+		desc.Max = 6
+		desc.Low = true
+		desc.Once = true
+		desc.Blinds = HoldemBlinds()
+		desc.Streets = HoldemStreets(5, 1, 3, 1, 1)
+		desc.Eval = typ
+		desc.LoComp = CompLo
+		// draw after flop
+		desc.Streets = append(desc.Streets[:2], append([]StreetDesc{{
+			Id:         'd',
+			Name:       "Draw",
+			PocketDraw: 5,
+		}}, desc.Streets[2:]...)...)
+		desc.Apply(opts...)
+	}
+}
+
+// WithDeucesWild is a type description option to set Deuces Wild
+// definitions.
+//
+// 5 cards, with deuces wild (see RankDeucesWild)
+// All 5 face down pre-flop
+// 1 round of player discards (up to 5)
+func WithDeucesWild(opts ...StreetOption) TypeOption {
+	return func(desc *TypeDesc) {
+		desc.Max = 6
+		desc.Streets = NumberedStreets(5, 0)
+		desc.Blinds = HoldemBlinds()
+		desc.Eval = EvalDeucesWild
+		desc.Streets[1].PocketDraw = 5
+		desc.Apply(opts...)
+	}
+}
+
+// WithJokersWild is a type description option to set Jokers Wild
+// definitions.
+//
+// Same as Deuces Wild, but with a deck having 2 jokers, and only the jokers
+// wild (see RankJokersWild).
+func WithJokersWild(opts ...StreetOption) TypeOption {
+	return func(desc *TypeDesc) {
+		WithDeucesWild(opts...)(desc)
+		desc.Deck = DeckJokers
+		desc.Eval = EvalJokersWild
+	}
+}
+
+// WithDrawBug is a type description option to set Draw with the Bug
+// definitions.
+//
+// Same as Deuces Wild, but with a deck having 1 joker, played as the "bug"
+// (see RankBug).
+func WithDrawBug(opts ...StreetOption) TypeOption {
+	return func(desc *TypeDesc) {
+		WithDeucesWild(opts...)(desc)
+		desc.Deck = DeckJoker
+		desc.Eval = EvalBug
+	}
+}
+
+// WithCalifornia is a type description option to set California Lowball
+// definitions.
+//
+// Same as Draw with the Bug, but with a Ace-to-Five low card ranking, and the
+// joker played as the lowest rank not in the hand (see RankAceFiveBug).
+func WithCalifornia(opts ...StreetOption) TypeOption {
+	return func(desc *TypeDesc) {
+		WithDrawBug(opts...)(desc)
+		desc.Max = 8
+		desc.Eval = EvalAceFiveBug
+	}
+}
+
 // StreetDesc is a type's street description.
 type StreetDesc struct {
 	// Id is the id of the street.
@@ -633,6 +897,19 @@ type StreetDesc struct {
 	Board int
 	// BoardDiscard is the count of cards to discard before board dealt.
 	BoardDiscard int
+	// Discard is the count of pocket cards each player discards (without
+	// replacement) at the end of the street.
+	Discard int
+}
+
+// lastPocket returns true when street i is the last street dealing pockets.
+func lastPocket(streets []StreetDesc, i int) bool {
+	for _, street := range streets[i+1:] {
+		if 0 < street.Pocket {
+			return false
+		}
+	}
+	return true
 }
 
 // HoldemBlinds returns the Holdem blind names.
@@ -677,7 +954,7 @@ func HoldemStreets(pocket, discard, flop, turn, river int) []StreetDesc {
 func StudStreets() []StreetDesc {
 	v := NumberedStreets(3, 1, 1, 1, 1)
 	for i := 0; i < 4; i++ {
-		v[0].PocketUp = 1
+		v[i].PocketUp = 1
 	}
 	return v
 }
@@ -732,10 +1009,39 @@ const (
 	EvalBadugi
 	EvalLowball
 	EvalSoko
+	EvalAceSix
+	EvalBadeucy
+	EvalBadacey
+	EvalDramaha
+	EvalDramahaDeuce
+	EvalDramadugi
+	EvalJokersWild
+	EvalDeucesWild
+	EvalBug
+	EvalAceFiveBug
 )
 
-// New creates the eval type.
+// New creates the eval type. When the eval type is not Wild, hands containing
+// a joker are ranked Invalid.
 func (typ EvalType) New(low bool) EvalFunc {
+	f := typ.eval(low)
+	if f == nil || typ.Wild() {
+		return f
+	}
+	return newJokerInvalidEval(f)
+}
+
+// Wild returns true when the eval type ranks hands containing jokers.
+func (typ EvalType) Wild() bool {
+	switch typ {
+	case EvalJokersWild, EvalDeucesWild, EvalBug, EvalAceFiveBug:
+		return true
+	}
+	return false
+}
+
+// eval creates the eval func for the eval type.
+func (typ EvalType) eval(low bool) EvalFunc {
 	switch typ {
 	case EvalHoldem:
 		return NewHoldemEval(DefaultRank, Five)
@@ -766,6 +1072,26 @@ func (typ EvalType) New(low bool) EvalFunc {
 		return NewLowballEval()
 	case EvalSoko:
 		return NewSokoEval()
+	case EvalAceSix:
+		return NewAceSixEval()
+	case EvalBadeucy:
+		return NewSplitEval(NewBadugiFiveEval(), NewLowballEval())
+	case EvalBadacey:
+		return NewSplitEval(NewBadugiFiveEval(), NewAceFiveEval())
+	case EvalDramaha:
+		return NewSplitEval(NewOmahaFiveEval(Invalid), NewHoldemEval(DefaultRank, Five))
+	case EvalDramahaDeuce:
+		return NewSplitEval(NewOmahaFiveEval(Invalid), NewLowballEval())
+	case EvalDramadugi:
+		return NewSplitEval(NewOmahaFiveEval(Invalid), NewBadugiFiveEval())
+	case EvalJokersWild:
+		return NewWildEval(Card.Joker, false)
+	case EvalDeucesWild:
+		return NewWildEval(deuceWild, false)
+	case EvalBug:
+		return NewWildEval(Card.Joker, true)
+	case EvalAceFiveBug:
+		return NewAceFiveBugEval()
 	}
 	return nil
 }
@@ -853,95 +1179,156 @@ func NewManilaEval() EvalFunc {
 
 // NewOmahaEval creates a Omaha hand rank eval func.
 func NewOmahaEval(loMax HandRank) EvalFunc {
-	return func(h *Hand) {
-		h.Init(5, 4, loMax)
-		v, r := make([]Card, 5), HandRank(0)
-		for i := 0; i < 6; i++ {
-			for j := 0; j < 10; j++ {
-				v[0], v[1] = h.Pocket[t4c2[i][0]], h.Pocket[t4c2[i][1]] // pocket
-				v[2], v[3] = h.Board[t5c3[j][0]], h.Board[t5c3[j][1]]   // board
-				v[4] = h.Board[t5c3[j][2]]                              // board
-				if r = DefaultRank(v); r < h.HiRank {
-					copy(h.HiBest, v)
-					h.HiRank = r
-					h.HiUnused[0], h.HiUnused[1] = h.Pocket[t4c2[i][2]], h.Pocket[t4c2[i][3]] // pocket
-					h.HiUnused[2], h.HiUnused[3] = h.Board[t5c3[j][3]], h.Board[t5c3[j][4]]   // board
-				}
-				if loMax != Invalid {
-					if r = HandRank(RankEightOrBetter(v[0], v[1], v[2], v[3], v[4])); r < h.LoRank && r < loMax {
-						copy(h.LoBest, v)
-						h.LoRank = r
-						h.LoUnused[0], h.LoUnused[1] = h.Pocket[t4c2[i][2]], h.Pocket[t4c2[i][3]] // pocket
-						h.LoUnused[2], h.LoUnused[3] = h.Board[t5c3[j][3]], h.Board[t5c3[j][4]]   // board
-					}
-				}
-			}
-		}
-		bestOmaha(h, loMax)
-	}
+	return newOmahaEval(4, loMax)
 }
 
 // NewOmahaFiveEval creates a new Omaha5 hand rank eval func.
 func NewOmahaFiveEval(loMax HandRank) EvalFunc {
+	return newOmahaEval(5, loMax)
+}
+
+// NewOmahaSixEval creates a new Omaha6 hand rank eval func.
+func NewOmahaSixEval(loMax HandRank) EvalFunc {
+	return newOmahaEval(6, loMax)
+}
+
+// newOmahaEval creates a Omaha hand rank eval func for a pocket of n cards,
+// using exactly 2 pocket cards and 3 board cards. Boards with 3, 4 or 5 cards
+// are supported.
+//
+// The rank bits, suit bits, prime product, and 8-or-better low bits of each
+// pocket pair and board triple are computed only once, and combined for each
+// pocket pair and board triple combination. Flushes are only looked up when
+// the combined suit bits are set, and lows are only ranked for the pocket pairs
+// and board triples having unique ranks of Eight or lower.
+//
+// The precomputed parts are equivalent to the package's Cactus Kev rank
+// funcs, and are only used when DefaultRank has not been changed. Otherwise,
+// every combination is ranked with DefaultRank.
+func newOmahaEval(n int, loMax HandRank) EvalFunc {
+	f := cactusFastParts
+	if f == nil {
+		f = cactusParts
+	}
+	pairs := combinations(n, 2)
+	triples := [6][][]uint8{3: combinations(3, 3), 4: combinations(4, 3), 5: combinations(5, 3)}
 	return func(h *Hand) {
-		h.Init(5, 5, loMax)
-		v, r := make([]Card, 5), HandRank(0)
-		for i := 0; i < 10; i++ {
-			for j := 0; j < 10; j++ {
-				v[0], v[1] = h.Pocket[t5c2[i][0]], h.Pocket[t5c2[i][1]] // pocket
-				v[2], v[3] = h.Board[t5c3[j][0]], h.Board[t5c3[j][1]]   // board
-				v[4] = h.Board[t5c3[j][2]]                              // board
-				if r = DefaultRank(v); r < h.HiRank {
-					copy(h.HiBest, v)
-					h.HiRank = r
-					h.HiUnused[0], h.HiUnused[1] = h.Pocket[t5c2[i][2]], h.Pocket[t5c2[i][3]] // pocket
-					h.HiUnused[2] = h.Pocket[t5c2[i][4]]                                      // pocket
-					h.HiUnused[3], h.HiUnused[4] = h.Board[t5c3[j][3]], h.Board[t5c3[j][4]]   // board
+		if len(h.Pocket) != n || len(h.Board) < 3 || 5 < len(h.Board) {
+			panic("bad hand")
+		}
+		h.Init(5, n-2+len(h.Board)-3, loMax)
+		t := triples[len(h.Board)]
+		if !isDefaultRank() {
+			loopOmaha(h, pairs, t, loMax)
+			bestOmaha(h, loMax)
+			return
+		}
+		// compute parts, and the pocket pairs and board triples with a low
+		lo := loMax != Invalid
+		var p [15]omahaPart
+		var lp [15]int
+		np := 0
+		for i, v := range pairs {
+			if p[i] = newOmahaPart(h.Pocket[v[0]], h.Pocket[v[1]]); lo && p[i].low != 0 {
+				lp[np], np = i, np+1
+			}
+		}
+		var b [10]omahaPart
+		var lt [10]int
+		nt := 0
+		for i, v := range t {
+			if b[i] = newOmahaPart(h.Board[v[0]], h.Board[v[1]], h.Board[v[2]]); lo && b[i].low != 0 {
+				lt[nt], nt = i, nt+1
+			}
+		}
+		// rank hi combinations
+		hi, low := [2]int{-1, -1}, [2]int{-1, -1}
+		for i := 0; i < len(pairs); i++ {
+			for j := 0; j < len(t); j++ {
+				if r := f(p[i].bits|b[j].bits, p[i].suits&b[j].suits, p[i].product*b[j].product); r < h.HiRank {
+					h.HiRank, hi = r, [2]int{i, j}
 				}
-				if loMax != Invalid {
-					if r = HandRank(RankEightOrBetter(v[0], v[1], v[2], v[3], v[4])); r < h.LoRank && r < loMax {
-						copy(h.LoBest, v)
-						h.LoRank = r
-						h.LoUnused[0], h.LoUnused[1] = h.Pocket[t5c2[i][2]], h.Pocket[t5c2[i][3]] // pocket
-						h.LoUnused[2] = h.Pocket[t5c2[i][4]]                                      // pocket
-						h.LoUnused[3], h.LoUnused[4] = h.Board[t5c3[j][3]], h.Board[t5c3[j][4]]   // board
-					}
+			}
+		}
+		// rank lo combinations
+		for _, i := range lp[:np] {
+			for _, j := range lt[:nt] {
+				if r := p[i].low | b[j].low; p[i].low&b[j].low == 0 && r < h.LoRank && r < loMax {
+					h.LoRank, low = r, [2]int{i, j}
 				}
 			}
 		}
+		// set best and unused
+		omahaBest(h.HiBest, h.HiUnused, h.Pocket, h.Board, pairs[hi[0]], t[hi[1]])
+		if low[0] != -1 {
+			omahaBest(h.LoBest, h.LoUnused, h.Pocket, h.Board, pairs[low[0]], t[low[1]])
+		}
 		bestOmaha(h, loMax)
 	}
 }
 
-// NewOmahaSixEval creates a new Omaha6 hand rank eval func.
-func NewOmahaSixEval(loMax HandRank) EvalFunc {
-	return func(h *Hand) {
-		h.Init(5, 6, loMax)
-		v, r := make([]Card, 5), HandRank(0)
-		for i := 0; i < 15; i++ {
-			for j := 0; j < 10; j++ {
-				v[0], v[1] = h.Pocket[t6c2[i][0]], h.Pocket[t6c2[i][1]] // pocket
-				v[2], v[3] = h.Board[t5c3[j][0]], h.Board[t5c3[j][1]]   // board
-				v[4] = h.Board[t5c3[j][2]]                              // board
-				if r = DefaultRank(v); r < h.HiRank {
-					copy(h.HiBest, v)
-					h.HiRank = r
-					h.HiUnused[0], h.HiUnused[1] = h.Pocket[t6c2[i][2]], h.Pocket[t6c2[i][3]] // pocket
-					h.HiUnused[2], h.HiUnused[3] = h.Pocket[t6c2[i][4]], h.Pocket[t6c2[i][5]] // pocket
-					h.HiUnused[4], h.HiUnused[5] = h.Board[t5c3[j][3]], h.Board[t5c3[j][4]]   // board
-				}
-				if loMax != Invalid {
-					if r = HandRank(RankEightOrBetter(v[0], v[1], v[2], v[3], v[4])); r < h.LoRank && r < loMax {
-						copy(h.LoBest, v)
-						h.LoRank = r
-						h.LoUnused[0], h.LoUnused[1] = h.Pocket[t6c2[i][2]], h.Pocket[t6c2[i][3]] // pocket
-						h.LoUnused[2], h.LoUnused[3] = h.Pocket[t6c2[i][4]], h.Pocket[t6c2[i][5]] // pocket
-						h.LoUnused[4], h.LoUnused[5] = h.Board[t5c3[j][3]], h.Board[t5c3[j][4]]   // board
-					}
-				}
+// loopOmaha ranks every pocket pair and board triple combination of the hand
+// with DefaultRank and RankEightOrBetter.
+func loopOmaha(h *Hand, pairs, triples [][]uint8, loMax HandRank) {
+	v := make([]Card, 5)
+	for _, p := range pairs {
+		for _, b := range triples {
+			v[0], v[1] = h.Pocket[p[0]], h.Pocket[p[1]]
+			v[2], v[3], v[4] = h.Board[b[0]], h.Board[b[1]], h.Board[b[2]]
+			if r := DefaultRank(v); r < h.HiRank {
+				h.HiRank = r
+				omahaBest(h.HiBest, h.HiUnused, h.Pocket, h.Board, p, b)
+			}
+			if loMax == Invalid {
+				continue
+			}
+			if r := RankEightOrBetter(v[0], v[1], v[2], v[3], v[4]); r < h.LoRank && r < loMax {
+				h.LoRank = r
+				omahaBest(h.LoBest, h.LoUnused, h.Pocket, h.Board, p, b)
 			}
 		}
-		bestOmaha(h, loMax)
+	}
+}
+
+// omahaPart is a precomputed part (ie, a pocket pair or a board triple) of a
+// Omaha hand.
+type omahaPart struct {
+	bits    uint32
+	suits   uint32
+	product uint32
+	low     HandRank
+}
+
+// newOmahaPart creates a new Omaha part for the cards. The low bits are only
+// set when the cards have unique ranks that are all Eight or lower (aces low).
+func newOmahaPart(v ...Card) omahaPart {
+	p, ok := omahaPart{suits: 0xf000, product: 1}, true
+	for _, c := range v {
+		p.bits |= uint32(c) >> 16
+		p.suits &= uint32(c) & 0xf000
+		p.product *= uint32(c) & 0xff
+		r := c.AceIndex()
+		ok = ok && r < 8 && p.low&(1<<r) == 0
+		p.low |= 1 << r
+	}
+	if !ok {
+		p.low = 0
+	}
+	return p
+}
+
+// omahaBest sets the best and unused cards for the pocket and board indexes.
+func omahaBest(best, unused, pocket, board []Card, p, b []uint8) {
+	best[0], best[1] = pocket[p[0]], pocket[p[1]]
+	best[2], best[3], best[4] = board[b[0]], board[b[1]], board[b[2]]
+	i := 0
+	for _, j := range p[2:] {
+		unused[i] = pocket[j]
+		i++
+	}
+	for _, j := range b[3:] {
+		unused[i] = board[j]
+		i++
 	}
 }
 
@@ -1026,6 +1413,131 @@ func NewBadugiEval() EvalFunc {
 	}
 }
 
+// NewBadugiFiveEval creates a Badugi hand rank eval func for a 5 card pocket,
+// evaluating the best Badugi hand from 4 of the 5 cards.
+func NewBadugiFiveEval() EvalFunc {
+	f, combos := NewBadugiEval(), combinations(5, 4)
+	return func(h *Hand) {
+		if len(h.Pocket) != 5 {
+			panic("bad pocket")
+		}
+		var best *Hand
+		for _, v := range combos {
+			b := &Hand{
+				Pocket: []Card{h.Pocket[v[0]], h.Pocket[v[1]], h.Pocket[v[2]], h.Pocket[v[3]]},
+			}
+			f(b)
+			if best == nil || b.HiRank < best.HiRank {
+				b.HiUnused = append(b.HiUnused, h.Pocket[v[4]])
+				best = b
+			}
+		}
+		sort.Slice(best.HiUnused, func(i, j int) bool {
+			if a, b := best.HiUnused[i].AceIndex(), best.HiUnused[j].AceIndex(); a != b {
+				return a > b
+			}
+			return best.HiUnused[i].Suit() < best.HiUnused[j].Suit()
+		})
+		h.HiRank, h.HiBest, h.HiUnused = best.HiRank, best.HiBest, best.HiUnused
+	}
+}
+
+// NewSplitEval creates a split pot hand rank eval func, using hi to evaluate
+// the hand's hi, and lo to evaluate the hand's lo using only the pocket. The
+// hand's lo is set from the hi of the lo evaluation.
+func NewSplitEval(hi, lo EvalFunc) EvalFunc {
+	return func(h *Hand) {
+		hi(h)
+		l := &Hand{
+			Pocket: h.Pocket,
+			HiRank: Invalid,
+			LoRank: Invalid,
+		}
+		lo(l)
+		h.LoRank, h.LoBest, h.LoUnused = l.HiRank, l.HiBest, l.HiUnused
+	}
+}
+
+// newJokerInvalidEval creates a eval func that ranks hands containing a joker
+// as Invalid, and otherwise evaluates the hand with f.
+func newJokerInvalidEval(f EvalFunc) EvalFunc {
+	return func(h *Hand) {
+		for _, v := range [][]Card{h.Pocket, h.Board} {
+			for _, c := range v {
+				if c.Joker() {
+					h.HiRank, h.LoRank = Invalid, Invalid
+					return
+				}
+			}
+		}
+		f(h)
+	}
+}
+
+// NewAceFiveEval creates a Ace-to-Five low hand rank eval func for a 5 card
+// pocket. Aces are low, straights and flushes do not count.
+func NewAceFiveEval() EvalFunc {
+	return func(h *Hand) {
+		if len(h.Pocket) != 5 {
+			panic("bad pocket")
+		}
+		h.HiRank = RankRazz(h.Pocket[0], h.Pocket[1], h.Pocket[2], h.Pocket[3], h.Pocket[4])
+		h.Init(5, 0, Invalid)
+		copy(h.HiBest, h.Pocket)
+		sort.Slice(h.HiBest, func(i, j int) bool {
+			return (h.HiBest[i].Rank()+1)%13 > (h.HiBest[j].Rank()+1)%13
+		})
+		if rankLowMax <= h.HiRank {
+			h.HiBest, _ = bestSet(h.HiBest)
+		}
+	}
+}
+
+// NewAceFiveBugEval creates a Ace-to-Five low hand rank eval func for a 5
+// card pocket, where jokers are the lowest rank not in the hand. The best
+// cards contain the cards assigned to the jokers. See RankAceFiveBug.
+func NewAceFiveBugEval() EvalFunc {
+	f := NewAceFiveEval()
+	return func(h *Hand) {
+		if len(h.Pocket) != 5 {
+			panic("bad pocket")
+		}
+		v := &Hand{
+			Pocket: aceFiveBug(h.Pocket),
+		}
+		f(v)
+		h.HiRank, h.HiBest, h.HiUnused = v.HiRank, v.HiBest, v.HiUnused
+	}
+}
+
+// NewWildEval creates a wild card high hand rank eval func for hands of 5, 6,
+// or 7 cards, using wild to determine the wild cards. When bug is true, wild
+// cards can only be an Ace, or a card completing a straight, flush, or
+// straight flush. See RankJokersWild and RankBug.
+//
+// The best cards contain the cards assigned to the wild cards.
+func NewWildEval(wild func(Card) bool, bug bool) EvalFunc {
+	combos := [8][][]uint8{5: combinations(5, 5), 6: combinations(6, 5), 7: combinations(7, 5)}
+	return func(h *Hand) {
+		hand := h.Hand()
+		if len(hand) < 5 || 7 < len(hand) {
+			panic("bad hand")
+		}
+		h.HiRank = Invalid
+		h.Init(0, len(hand)-5, Invalid)
+		for _, v := range combos[len(hand)] {
+			r, best := rankWild([]Card{hand[v[0]], hand[v[1]], hand[v[2]], hand[v[3]], hand[v[4]]}, wild, bug)
+			if r < h.HiRank {
+				h.HiRank, h.HiBest = r, best
+				for i, j := range v[5:] {
+					h.HiUnused[i] = hand[j]
+				}
+			}
+		}
+		sortHigh(h.HiUnused)
+	}
+}
+
 // NewLowballEval creates a Lowball hand rank eval func.
 func NewLowballEval() EvalFunc {
 	f := NewRankFunc(RankLowball)
@@ -1036,14 +1548,58 @@ func NewLowballEval() EvalFunc {
 		h.HiRank = f(h.Pocket)
 		h.Init(5, 0, Invalid)
 		copy(h.HiBest, h.Pocket)
+		bestLowball(h)
 	}
 }
 
 // NewSokoEval creates a Soko hand rank eval func.
 func NewSokoEval() EvalFunc {
-	f := NewHoldemEval(DefaultRank, Five)
 	return func(h *Hand) {
-		f(h)
+		hand := h.Hand()
+		if len(hand) != 5 {
+			panic("bad hand")
+		}
+		h.HiRank = RankSoko(hand[0], hand[1], hand[2], hand[3], hand[4])
+		bestSoko(h, hand)
+	}
+}
+
+// NewAceSixEval creates a Ace-to-Six hand rank eval func, for hands of 5, 6,
+// or 7 cards.
+func NewAceSixEval() EvalFunc {
+	f := NewRankFunc(RankLowball)
+	combos := [8][][]uint8{5: combinations(5, 5), 6: combinations(6, 5), 7: combinations(7, 5)}
+	return func(h *Hand) {
+		hand := h.Hand()
+		if len(hand) < 5 || 7 < len(hand) {
+			panic("bad hand")
+		}
+		// rank as Two-to-Seven with aces relabeled low
+		for i, c := range hand {
+			hand[i] = aceLow(c)
+		}
+		h.HiRank = f(hand)
+		h.Init(5, len(hand)-5, Invalid)
+		for _, v := range combos[len(hand)] {
+			if RankLowball(hand[v[0]], hand[v[1]], hand[v[2]], hand[v[3]], hand[v[4]]) == h.HiRank {
+				for i, j := range v {
+					if i < 5 {
+						h.HiBest[i] = hand[j]
+					} else {
+						h.HiUnused[i-5] = hand[j]
+					}
+				}
+				break
+			}
+		}
+		bestLowball(h)
+		// restore ranks
+		for i, c := range h.HiBest {
+			h.HiBest[i] = aceHigh(c)
+		}
+		for i, c := range h.HiUnused {
+			h.HiUnused[i] = aceHigh(c)
+		}
 	}
 }
 
@@ -1140,17 +1696,20 @@ func ManilaComp(a, b *Hand, _ HandRank) int {
 }
 
 // LowballComp is the Lowball compare func.
-func LowballComp(a, b *Hand, _ HandRank) int {
-	switch af, bf := rankMax-a.HiRank, rankMax-b.HiRank; {
-	case af == bf:
-	}
-	// log.Printf(">>> a: %v // b: %v", a.HiRank, b.HiRank)
-	return 0
+//
+// Two-to-Seven hand ranks are ordered low-to-high, with straights and flushes
+// counting against the hand, and 5-4-3-2-A ranking as a Ace-high hand. As
+// the ranks are already ordered best first, LowballComp is HiComp.
+func LowballComp(a, b *Hand, loMax HandRank) int {
+	return HiComp(a, b, loMax)
 }
 
 // SokoComp is the Soko compare func.
-func SokoComp(a, b *Hand, _ HandRank) int {
-	return 0
+//
+// Four flushes and four straights are ranked between two pair and a pair
+// (see RankSoko), so Soko hands order the same way as with HiComp.
+func SokoComp(a, b *Hand, loMax HandRank) int {
+	return HiComp(a, b, loMax)
 }
 
 // IdToType converts an id to a type.
@@ -1220,6 +1779,91 @@ func bestOmaha(h *Hand, loMax HandRank) {
 	}
 }
 
+// bestLowball orders the best Two-to-Seven hand high to low, with matching
+// sets first.
+func bestLowball(h *Hand) {
+	sort.Slice(h.HiBest, func(i, j int) bool {
+		m, n := h.HiBest[i].Rank(), h.HiBest[j].Rank()
+		if m == n {
+			return h.HiBest[i].Suit() > h.HiBest[j].Suit()
+		}
+		return m > n
+	})
+	switch lowballCactus(h.HiRank).Fixed() {
+	case StraightFlush:
+		h.HiBest, _ = bestStraightFlush(h.HiBest, Five)
+	case Straight:
+		h.HiBest, _ = bestStraight(h.HiBest, Five)
+	case FourOfAKind, FullHouse, ThreeOfAKind, TwoPair, Pair:
+		h.HiBest, _ = bestSet(h.HiBest)
+	}
+}
+
+// bestSoko sets the best Soko hand.
+func bestSoko(h *Hand, hand []Card) {
+	switch r := h.HiRank; {
+	case r <= TwoPair:
+		bestHoldem(h, hand, Five)
+		return
+	case r <= sokoFourFlush:
+		sortHigh(hand)
+		h.HiBest, h.HiUnused = bestFlush(hand)
+	case r <= sokoFourStraight:
+		sortHigh(hand)
+		h.HiBest, h.HiUnused = bestFourStraight(hand)
+	case (r - sokoShift).Fixed() == Nothing:
+		sortHigh(hand)
+		h.HiBest, h.HiUnused = hand[:5], hand[5:]
+	default:
+		sortHigh(hand)
+		h.HiBest, h.HiUnused = bestSet(hand)
+	}
+}
+
+// sortHigh orders a hand's cards high to low.
+func sortHigh(hand []Card) {
+	sort.Slice(hand, func(i, j int) bool {
+		m, n := hand[i].Rank(), hand[j].Rank()
+		if m == n {
+			return hand[i].Suit() > hand[j].Suit()
+		}
+		return m > n
+	})
+}
+
+// bestFourStraight returns the best four-card straight in the hand, followed
+// by the remaining cards.
+func bestFourStraight(hand []Card) ([]Card, []Card) {
+	m := make(map[Rank][]Card)
+	for _, c := range hand {
+		r := c.Rank()
+		m[r] = append(m[r], c)
+	}
+	var b []Card
+	for i := Ace; i >= Four; i-- {
+		// last card index
+		j := i - 3
+		// check ace
+		if i == Four {
+			j = Ace
+		}
+		if m[i] != nil && m[i-1] != nil && m[i-2] != nil && m[j] != nil {
+			// collect b, removing from m
+			b = []Card{m[i][0], m[i-1][0], m[i-2][0], m[j][0]}
+			m[i] = m[i][1:]
+			m[i-1] = m[i-1][1:]
+			m[i-2] = m[i-2][1:]
+			m[j] = m[j][1:]
+			break
+		}
+	}
+	// collect remaining
+	for i := int(Ace); i >= 0; i-- {
+		b = append(b, m[Rank(i)]...)
+	}
+	return b[:5], b[5:]
+}
+
 // bestStraightFlush returns the best-five straight flush in the hand.
 func bestStraightFlush(hand []Card, high Rank) ([]Card, []Card) {
 	v := orderSuits(hand)
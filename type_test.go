@@ -1,6 +1,8 @@
 package cardrank
 
 import (
+	"errors"
+	"math/rand"
 	"reflect"
 	"testing"
 )
@@ -105,6 +107,15 @@ func TestLowball(t *testing.T) {
 		{"8h 7h 6h 5h 2c", "8h 7h 6h 5h 2c", "", 17},
 		{"8h 7h 6h 5h 3c", "8h 7h 6h 5h 3c", "", 18},
 		{"9h 5h 4h 3h 2c", "9h 5h 4h 3h 2c", "", 19},
+		{"Kh Qh Jh Th 8c", "Kh Qh Jh Th 8c", "", 784},
+		{"2c 3h 4h 5h Ac", "Ac 5h 4h 3h 2c", "", 785},
+		{"Ac 6h 4h 3h 2c", "Ac 6h 4h 3h 2c", "", 786},
+		{"7h 2c 5h 7c 3h", "7c 7h 5h 3h 2c", "", 2380},
+		{"6s 5h 4h 3h 2c", "6s 5h 4h 3h 2c", "", 5856},
+		{"7h 5h 4h 3h 2h", "7h 5h 4h 3h 2h", "", 5865},
+		{"Ah 5h 4h 3h 2h", "Ah 5h 4h 3h 2h", "", 6649},
+		{"Ah 6h 4h 3h 2h", "Ah 6h 4h 3h 2h", "", 6650},
+		{"6h 5h 4h 3h 2h", "6h 5h 4h 3h 2h", "", 7456},
 	}
 	for i, test := range tests {
 		best, unused := Must(test.b), Must(test.u)
@@ -121,6 +132,430 @@ func TestLowball(t *testing.T) {
 	}
 }
 
+func TestSoko(t *testing.T) {
+	tests := []struct {
+		v    string
+		b    string
+		r    HandRank
+		desc string
+	}{
+		{"Ah Kh Qh Jh Th", "Ah Kh Qh Jh Th", 1, "Straight Flush, Ace-high, Royal"},
+		{"9c 9d 4h 4s 2c", "9c 9d 4h 4s 2c", 3072, "Two Pair, Nines over Fours, kicker Two"},
+		{"Ah Kh Qh Jh 2c", "Ah Kh Qh Jh 2c", 3338, "Four Flush, Ace-high, kicker Two"},
+		{"8c 8h 5h 4h 2h", "8h 5h 4h 2h 8c", 12393, "Four Flush, Eight-high, kicker Eight"},
+		{"5h 4h 3h 2h 7c", "5h 4h 3h 2h 7c", 12615, "Four Flush, Five-high, kicker Seven"},
+		{"Ah Kc Qh Js 9c", "Ah Kc Qh Js 9c", 12626, "Four Straight, Ace-high, kicker Nine"},
+		{"8c 7h 6h 5s 5c", "8c 7h 6h 5c 5s", 12708, "Four Straight, Eight-high, kicker Five"},
+		{"4c 3h 2h As 9c", "4c 3h 2h As 9c", 12756, "Four Straight, Four-high, kicker Nine"},
+		{"Ac Ah Kh 9s 7c", "Ac Ah Kh 9s 7c", 12792, "Pair, Aces, kickers King, Nine, Seven"},
+		{"Ac Qh Th 8s 6c", "Ac Qh Th 8s 6c", 15832, "Nothing, Ace-high, kickers Queen, Ten, Eight, Six"},
+	}
+	for i, test := range tests {
+		best := Must(test.b)
+		h := Soko.RankHand(Must(test.v), nil)
+		if h.HiRank != test.r {
+			t.Errorf("test %d %v expected rank %d, got: %d", i, h.Pocket, test.r, h.HiRank)
+		}
+		if !reflect.DeepEqual(h.HiBest, best) {
+			t.Errorf("test %d %v expected best %v, got: %v", i, h.Pocket, best, h.HiBest)
+		}
+		if s := h.Description(); s != test.desc {
+			t.Errorf("test %d %v expected %q, got: %q", i, h.Pocket, test.desc, s)
+		}
+		if 0 < i {
+			prev := Soko.RankHand(Must(tests[i-1].v), nil)
+			if n := prev.HiComp(h); n != -1 {
+				t.Errorf("test %d expected %v to beat %v, got: %d", i, prev.Pocket, h.Pocket, n)
+			}
+		}
+	}
+}
+
+func TestAceSix(t *testing.T) {
+	tests := []struct {
+		typ  Type
+		v    string
+		b    string
+		u    string
+		r    HandRank
+		desc string
+	}{
+		{AceSix, "2c 3h Ah 4h 6h", "6h 4h 3h 2c Ah", "", 1, "Six, Four, Three, Two, Ace-low"},
+		{AceSix, "6c 5h Ah 4h 2h", "6c 5h 4h 2h Ah", "", 3, "Six, Five, Four, Two, Ace-low"},
+		{AceSix, "7c 4h Ah 3h 2h", "7c 4h 3h 2h Ah", "", 5, "Seven, Four, Three, Two, Ace-low"},
+		{AceSix, "Kc Qh Jh Th Ah", "Kc Qh Jh Th Ah", "", 1271, "King, Queen, Jack, Ten, Ace-low"},
+		{AceSix, "Ac Ah 4h 3h 2h", "Ac Ah 4h 3h 2h", "", 1279, "Pair, Aces, kickers Four, Three, Two"},
+		{AceSix, "Kc Kh Ac Ah 2h", "Kc Kh Ac Ah 2h", "", 4865, "Two Pair, Kings over Aces, kicker Two"},
+		{AceSix, "5c 4h 3h 2h Ah", "5c 4h 3h 2h Ah", "", 5856, "Straight, Five-high"},
+		{AceSix, "6h 5h 4h 3h 2c", "6h 5h 4h 3h 2c", "", 5857, "Straight, Six-high"},
+		{AceSix, "6h 4h 3h 2h Ah", "6h 4h 3h 2h Ah", "", 5865, "Flush, Six-high"},
+		{AceSix, "5h 4h 3h 2h Ah", "5h 4h 3h 2h Ah", "", 7456, "Straight Flush, Five-high, Steel Wheel"},
+		{London, "Ks Qd 6h 4c 3d 2s Ah", "6h 4c 3d 2s Ah", "Ks Qd", 1, "Six, Four, Three, Two, Ace-low"},
+		{London, "As Ad Kh Kd 5h 4h 3h", "Kh 5h 4h 3h As", "Ad Kd", 788, "King, Five, Four, Three, Ace-low"},
+	}
+	for i, test := range tests {
+		best, unused := Must(test.b), Must(test.u)
+		h := test.typ.RankHand(Must(test.v), nil)
+		if h.HiRank != test.r {
+			t.Errorf("test %d %v expected rank %d, got: %d", i, h.Pocket, test.r, h.HiRank)
+		}
+		if !reflect.DeepEqual(h.HiBest, best) {
+			t.Errorf("test %d %v expected best %v, got: %v", i, h.Pocket, best, h.HiBest)
+		}
+		if !reflect.DeepEqual(h.HiUnused, unused) {
+			t.Errorf("test %d %v expected unused %v, got: %v", i, h.Pocket, unused, h.HiUnused)
+		}
+		if s := h.Description(); s != test.desc {
+			t.Errorf("test %d %v expected %q, got: %q", i, h.Pocket, test.desc, s)
+		}
+		if 0 < i && tests[i-1].typ == test.typ {
+			prev := test.typ.RankHand(Must(tests[i-1].v), nil)
+			if n := prev.HiComp(h); n != -1 {
+				t.Errorf("test %d expected %v to beat %v, got: %d", i, prev.Pocket, h.Pocket, n)
+			}
+		}
+	}
+}
+
+func TestBadugiSplit(t *testing.T) {
+	tests := []struct {
+		typ    Type
+		v      string
+		hiBest string
+		hi     string
+		loBest string
+		lo     string
+	}{
+		{Badeucy, "Kh Qc 4d 3s 2s", "Kh Qc 4d 2s", "King, Queen, Four, Two-low", "Kh Qc 4d 3s 2s", "King, Queen, Four, Three, Two-low"},
+		{Badeucy, "7h 5c 4d 3h 2s", "5c 4d 3h 2s", "Five, Four, Three, Two-low", "7h 5c 4d 3h 2s", "Seven, Five, Four, Three, Two-low, Wheel"},
+		{Badeucy, "Ah 2c 3d 4s 5h", "4s 3d 2c Ah", "Four, Three, Two, Ace-low", "Ah 5h 4s 3d 2c", "Ace, Five, Four, Three, Two-low"},
+		{Badeucy, "8h 8c 8d 8s Kh", "Kh 8d", "King, Eight-low", "8c 8d 8h 8s Kh", "Four of a Kind, Eights, kicker King"},
+		{Badacey, "Kh Qc 4d 3s 2s", "Kh Qc 4d 2s", "King, Queen, Four, Two-low", "Kh Qc 4d 3s 2s", "King, Queen, Four, Three, Two-low"},
+		{Badacey, "Ah 2c 3d 4s 5h", "4s 3d 2c Ah", "Four, Three, Two, Ace-low", "5h 4s 3d 2c Ah", "Five, Four, Three, Two, Ace-low"},
+		{Badacey, "Ah Ac 3d 4s 5h", "5h 4s 3d Ac", "Five, Four, Three, Ace-low", "Ah Ac 5h 4s 3d", "Pair, Aces, kickers Five, Four, Three"},
+	}
+	for i, test := range tests {
+		h := test.typ.RankHand(Must(test.v), nil)
+		if !reflect.DeepEqual(h.HiBest, Must(test.hiBest)) {
+			t.Errorf("test %d %v expected hi best %v, got: %v", i, h.Pocket, test.hiBest, h.HiBest)
+		}
+		if s := h.Description(); s != test.hi {
+			t.Errorf("test %d %v expected %q, got: %q", i, h.Pocket, test.hi, s)
+		}
+		if !reflect.DeepEqual(h.LoBest, Must(test.loBest)) {
+			t.Errorf("test %d %v expected lo best %v, got: %v", i, h.Pocket, test.loBest, h.LoBest)
+		}
+		if s := h.LowDescription(); s != test.lo {
+			t.Errorf("test %d %v expected %q, got: %q", i, h.Pocket, test.lo, s)
+		}
+	}
+	// hi and lo split between different hands
+	for _, typ := range []Type{Badeucy, Badacey} {
+		hands := typ.RankHands([][]Card{Must("Ah 2c 3d 4s Kh"), Must("8h 6c 4d 3h 2s")}, nil)
+		win := NewWin(hands, nil, typ.Low())
+		if win.HiPivot != 1 || win.Hi[0] != 0 {
+			t.Errorf("%s expected hand 0 to win hi, got: %v", typ, win.Hi)
+		}
+		if win.LoPivot != 1 || win.Lo[0] != 1 {
+			t.Errorf("%s expected hand 1 to win lo, got: %v", typ, win.Lo)
+		}
+	}
+}
+
+func TestDramaha(t *testing.T) {
+	tests := []struct {
+		typ    Type
+		pocket string
+		board  string
+		hi     string
+		loBest string
+		lo     string
+	}{
+		{Dramaha, "Ah Kh 7c 7d 2s", "Qh Jh Th 7h 3c", "Straight Flush, Ace-high, Royal", "7c 7d Ah Kh 2s", "Pair, Sevens, kickers Ace, King, Two"},
+		{DramahaDeuce, "Ah Kh 7c 7d 2s", "Qh Jh Th 7h 3c", "Straight Flush, Ace-high, Royal", "7c 7d Ah Kh 2s", "Pair, Sevens, kickers Ace, King, Two"},
+		{DramahaDeuce, "8c 6d 5h 4s 2c", "Kc Kd 8h 3c Js", "Two Pair, Kings over Eights, kicker Six", "8c 6d 5h 4s 2c", "Eight, Six, Five, Four, Two-low"},
+		{Dramadugi, "8c 6d 5h 4s 2c", "Kc Kd 8h 3c Js", "Two Pair, Kings over Eights, kicker Six", "6d 5h 4s 2c", "Six, Five, Four, Two-low"},
+	}
+	for i, test := range tests {
+		h := test.typ.RankHand(Must(test.pocket), Must(test.board))
+		if s := h.Description(); s != test.hi {
+			t.Errorf("test %d %v expected %q, got: %q", i, h.Pocket, test.hi, s)
+		}
+		if !reflect.DeepEqual(h.LoBest, Must(test.loBest)) {
+			t.Errorf("test %d %v expected lo best %v, got: %v", i, h.Pocket, test.loBest, h.LoBest)
+		}
+		if s := h.LowDescription(); s != test.lo {
+			t.Errorf("test %d %v expected %q, got: %q", i, h.Pocket, test.lo, s)
+		}
+	}
+	// hi and lo split between different hands
+	pockets := [][]Card{Must("Ah Kh 7c 7d 2s"), Must("8c 6d 5h 4s 2c")}
+	for _, typ := range []Type{Dramaha, DramahaDeuce} {
+		win := NewWin(typ.RankHands(pockets, Must("Qh Jh Th 8h 3c")), nil, typ.Low())
+		exp := 0
+		if typ == DramahaDeuce {
+			exp = 1
+		}
+		if win.HiPivot != 1 || win.Hi[0] != 0 {
+			t.Errorf("%s expected hand 0 to win hi, got: %v", typ, win.Hi)
+		}
+		if win.LoPivot != 1 || win.Lo[0] != exp {
+			t.Errorf("%s expected hand %d to win lo, got: %v", typ, exp, win.Lo)
+		}
+	}
+	// streets
+	pockets, board := NewDealer(Dramaha.Desc(), rand.New(rand.NewSource(0)), 1).DealAll(6)
+	if len(pockets) != 6 || len(pockets[0]) != 5 || len(board) != 5 {
+		t.Errorf("expected 6 pockets of 5 cards and 5 board cards, got: %v %v", pockets, board)
+	}
+}
+
+func TestJokerInvalid(t *testing.T) {
+	tests := []struct {
+		typ    Type
+		pocket string
+		board  string
+	}{
+		{Holdem, "Xb Ah", "Kh Qh Jh 2c 3d"},
+		{Holdem, "Ah Kh", "Qh Jh Xr 2c 3d"},
+		{Short, "Xb Ah", "Kh Qh Jh 7c 8d"},
+		{Manila, "Xb Ah", "Kh Qh Jh 7c 8d"},
+		{Omaha, "Xb Ah 2c 3c", "Kh Qh Jh 2d 3d"},
+		{OmahaHiLo, "Xb Ah 2c 3c", "Kh Qh Jh 4d 5d"},
+		{OmahaSix, "Xb Ah 2c 3c 4c 5c", "Kh Qh Jh 2d 3d"},
+		{Stud, "Xb Ah Kh Qh Jh 2c 3d", ""},
+		{Razz, "Xb Ah 2h 3h 4h 5c 6d", ""},
+		{Badugi, "Xb Ah 2c 3d", ""},
+		{Lowball, "Xb 7h 5c 4d 3s", ""},
+		{Soko, "Xb Ah Kh Qh Jh", ""},
+		{AceSix, "Xb 6h 4c 3d 2s", ""},
+		{Badeucy, "Xb 7h 5c 4d 3s", ""},
+		{Dramaha, "Xb Ah Kh 2c 3d", "Qh Jh Th 7h 3c"},
+	}
+	for i, test := range tests {
+		h := test.typ.RankHand(Must(test.pocket), Must(test.board))
+		if h.HiRank != Invalid || h.LoRank != Invalid {
+			t.Errorf("test %d %s expected Invalid, got: %d %d", i, test.typ, h.HiRank, h.LoRank)
+		}
+		if s := h.Description(); s != "Invalid" {
+			t.Errorf("test %d %s expected %q, got: %q", i, test.typ, "Invalid", s)
+		}
+	}
+	desc := descs[Holdem]
+	desc.Type, desc.Deck = 'X'<<8|'h', DeckJokers
+	if err := RegisterType(desc); !errors.Is(err, ErrInvalidDeck) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidDeck, err)
+	}
+}
+
+func TestOmahaEval(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	for _, test := range []struct {
+		typ  Type
+		n    int
+		loop func(HandRank) EvalFunc
+	}{
+		{Omaha, 4, loopOmahaEval},
+		{OmahaHiLo, 4, loopOmahaEval},
+		{OmahaFive, 5, loopOmahaFiveEval},
+		{CourchevelHiLo, 5, loopOmahaFiveEval},
+		{OmahaSix, 6, loopOmahaSixEval},
+	} {
+		typ, n, loMax := test.typ, test.n, Invalid
+		if typ.Low() {
+			loMax = rankEightOrBetterMax
+		}
+		for m := 3; m <= 5; m++ {
+			for i := 0; i < 2000; i++ {
+				d := NewDeck()
+				d.Shuffle(r)
+				pocket, board := d.Draw(n), d.Draw(m)
+				h, exp := NewHand(typ, pocket, board), NewUnevaluatedHand(typ, pocket, board)
+				bruteOmaha(exp, loMax)
+				if !reflect.DeepEqual(h, exp) {
+					t.Fatalf("%s %d %d expected:\n%#v\ngot:\n%#v", typ, m, i, exp, h)
+				}
+				// the loop evals only support a complete board
+				if m != 5 {
+					continue
+				}
+				exp = NewUnevaluatedHand(typ, pocket, board)
+				test.loop(loMax)(exp)
+				if !reflect.DeepEqual(h, exp) {
+					t.Fatalf("%s %d %d expected:\n%#v\ngot:\n%#v", typ, m, i, exp, h)
+				}
+			}
+		}
+	}
+}
+
+func TestOmahaEvalDefaultRank(t *testing.T) {
+	if !isDefaultRank() {
+		t.Fatalf("expected default rank")
+	}
+	orig := DefaultRank
+	defer func() {
+		DefaultRank = orig
+	}()
+	// count the calls to the rank func
+	var count int
+	DefaultRank = func(v []Card) HandRank {
+		count++
+		return orig(v)
+	}
+	if isDefaultRank() {
+		t.Fatalf("expected changed default rank")
+	}
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 1000; i++ {
+		d := NewDeck()
+		d.Shuffle(r)
+		pocket, board := d.Draw(4), d.Draw(5)
+		count = 0
+		h := NewHand(OmahaHiLo, pocket, board)
+		if count != 6*10 {
+			t.Fatalf("test %d expected %d calls, got: %d", i, 6*10, count)
+		}
+		exp := NewUnevaluatedHand(OmahaHiLo, pocket, board)
+		loopOmahaEval(rankEightOrBetterMax)(exp)
+		if !reflect.DeepEqual(h, exp) {
+			t.Fatalf("test %d expected:\n%#v\ngot:\n%#v", i, exp, h)
+		}
+	}
+}
+
+// bruteOmaha is a brute force Omaha eval, evaluating every pocket pair and
+// board triple.
+func bruteOmaha(h *Hand, loMax HandRank) {
+	pairs, triples := combinations(len(h.Pocket), 2), combinations(len(h.Board), 3)
+	h.Init(5, len(h.Pocket)-2+len(h.Board)-3, loMax)
+	v, r := make([]Card, 5), HandRank(0)
+	for _, p := range pairs {
+		for _, b := range triples {
+			v[0], v[1] = h.Pocket[p[0]], h.Pocket[p[1]]
+			v[2], v[3], v[4] = h.Board[b[0]], h.Board[b[1]], h.Board[b[2]]
+			var unused []Card
+			for _, j := range p[2:] {
+				unused = append(unused, h.Pocket[j])
+			}
+			for _, j := range b[3:] {
+				unused = append(unused, h.Board[j])
+			}
+			if r = DefaultRank(v); r < h.HiRank {
+				copy(h.HiBest, v)
+				copy(h.HiUnused, unused)
+				h.HiRank = r
+			}
+			if loMax != Invalid {
+				if r = RankEightOrBetter(v[0], v[1], v[2], v[3], v[4]); r < h.LoRank && r < loMax {
+					copy(h.LoBest, v)
+					copy(h.LoUnused, unused)
+					h.LoRank = r
+				}
+			}
+		}
+	}
+	bestOmaha(h, loMax)
+}
+
+// loopOmahaEval is the Omaha eval before the precomputed pocket pair and board
+// triple eval, looping over every pocket pair and board triple of a 5 card
+// board. Used to check the current evals produce identical hands.
+func loopOmahaEval(loMax HandRank) EvalFunc {
+	return func(h *Hand) {
+		h.Init(5, 4, loMax)
+		v, r := make([]Card, 5), HandRank(0)
+		for i := 0; i < 6; i++ {
+			for j := 0; j < 10; j++ {
+				v[0], v[1] = h.Pocket[t4c2[i][0]], h.Pocket[t4c2[i][1]] // pocket
+				v[2], v[3] = h.Board[t5c3[j][0]], h.Board[t5c3[j][1]]   // board
+				v[4] = h.Board[t5c3[j][2]]                              // board
+				if r = DefaultRank(v); r < h.HiRank {
+					copy(h.HiBest, v)
+					h.HiRank = r
+					h.HiUnused[0], h.HiUnused[1] = h.Pocket[t4c2[i][2]], h.Pocket[t4c2[i][3]] // pocket
+					h.HiUnused[2], h.HiUnused[3] = h.Board[t5c3[j][3]], h.Board[t5c3[j][4]]   // board
+				}
+				if loMax != Invalid {
+					if r = HandRank(RankEightOrBetter(v[0], v[1], v[2], v[3], v[4])); r < h.LoRank && r < loMax {
+						copy(h.LoBest, v)
+						h.LoRank = r
+						h.LoUnused[0], h.LoUnused[1] = h.Pocket[t4c2[i][2]], h.Pocket[t4c2[i][3]] // pocket
+						h.LoUnused[2], h.LoUnused[3] = h.Board[t5c3[j][3]], h.Board[t5c3[j][4]]   // board
+					}
+				}
+			}
+		}
+		bestOmaha(h, loMax)
+	}
+}
+
+// loopOmahaFiveEval is the Omaha5 eval before the precomputed eval. See
+// loopOmahaEval.
+func loopOmahaFiveEval(loMax HandRank) EvalFunc {
+	return func(h *Hand) {
+		h.Init(5, 5, loMax)
+		v, r := make([]Card, 5), HandRank(0)
+		for i := 0; i < 10; i++ {
+			for j := 0; j < 10; j++ {
+				v[0], v[1] = h.Pocket[t5c2[i][0]], h.Pocket[t5c2[i][1]] // pocket
+				v[2], v[3] = h.Board[t5c3[j][0]], h.Board[t5c3[j][1]]   // board
+				v[4] = h.Board[t5c3[j][2]]                              // board
+				if r = DefaultRank(v); r < h.HiRank {
+					copy(h.HiBest, v)
+					h.HiRank = r
+					h.HiUnused[0], h.HiUnused[1] = h.Pocket[t5c2[i][2]], h.Pocket[t5c2[i][3]] // pocket
+					h.HiUnused[2] = h.Pocket[t5c2[i][4]]                                      // pocket
+					h.HiUnused[3], h.HiUnused[4] = h.Board[t5c3[j][3]], h.Board[t5c3[j][4]]   // board
+				}
+				if loMax != Invalid {
+					if r = HandRank(RankEightOrBetter(v[0], v[1], v[2], v[3], v[4])); r < h.LoRank && r < loMax {
+						copy(h.LoBest, v)
+						h.LoRank = r
+						h.LoUnused[0], h.LoUnused[1] = h.Pocket[t5c2[i][2]], h.Pocket[t5c2[i][3]] // pocket
+						h.LoUnused[2] = h.Pocket[t5c2[i][4]]                                      // pocket
+						h.LoUnused[3], h.LoUnused[4] = h.Board[t5c3[j][3]], h.Board[t5c3[j][4]]   // board
+					}
+				}
+			}
+		}
+		bestOmaha(h, loMax)
+	}
+}
+
+// loopOmahaSixEval is the Omaha6 eval before the precomputed eval. See
+// loopOmahaEval.
+func loopOmahaSixEval(loMax HandRank) EvalFunc {
+	return func(h *Hand) {
+		h.Init(5, 6, loMax)
+		v, r := make([]Card, 5), HandRank(0)
+		for i := 0; i < 15; i++ {
+			for j := 0; j < 10; j++ {
+				v[0], v[1] = h.Pocket[t6c2[i][0]], h.Pocket[t6c2[i][1]] // pocket
+				v[2], v[3] = h.Board[t5c3[j][0]], h.Board[t5c3[j][1]]   // board
+				v[4] = h.Board[t5c3[j][2]]                              // board
+				if r = DefaultRank(v); r < h.HiRank {
+					copy(h.HiBest, v)
+					h.HiRank = r
+					h.HiUnused[0], h.HiUnused[1] = h.Pocket[t6c2[i][2]], h.Pocket[t6c2[i][3]] // pocket
+					h.HiUnused[2], h.HiUnused[3] = h.Pocket[t6c2[i][4]], h.Pocket[t6c2[i][5]] // pocket
+					h.HiUnused[4], h.HiUnused[5] = h.Board[t5c3[j][3]], h.Board[t5c3[j][4]]   // board
+				}
+				if loMax != Invalid {
+					if r = HandRank(RankEightOrBetter(v[0], v[1], v[2], v[3], v[4])); r < h.LoRank && r < loMax {
+						copy(h.LoBest, v)
+						h.LoRank = r
+						h.LoUnused[0], h.LoUnused[1] = h.Pocket[t6c2[i][2]], h.Pocket[t6c2[i][3]] // pocket
+						h.LoUnused[2], h.LoUnused[3] = h.Pocket[t6c2[i][4]], h.Pocket[t6c2[i][5]] // pocket
+						h.LoUnused[4], h.LoUnused[5] = h.Board[t5c3[j][3]], h.Board[t5c3[j][4]]   // board
+					}
+				}
+			}
+		}
+		bestOmaha(h, loMax)
+	}
+}
+
 func TestTypeHiComp(t *testing.T) {
 	tests := []struct {
 		typ   Type
@@ -149,12 +584,17 @@ func TestTypeHiComp(t *testing.T) {
 		{Omaha, "Tc 6c 2s 3s As", "9h 9d 4h 4d", "Kd Qs Js 8h", Pair, Flush, +1},
 		{Omaha, "4s 3h 6c 2d Kd", "Kh Qs 5h 2c", "7s 7c 4h 2s", Straight, TwoPair, -1},
 		{Omaha, "4s 3h 6c 2d Kd", "7s 7c 4h 2s", "Kh Qs 5h 2c", TwoPair, Straight, +1},
-		/*
-			{Lowball, "7h 5h 4h 3h 2c", "7h 6h 4h 3h 2c", ""},
-			{Lowball, "7h 6h 4h 3h 2c", "", ""},
-			{Lowball, "", "", ""},
-			{Lowball, "", "", ""},
-		*/
+		{Lowball, "", "7h 5h 4h 3h 2c", "7h 6h 4h 3h 2c", Nothing, Nothing, -1},
+		{Lowball, "", "7h 6h 4h 3h 2c", "7h 5h 4h 3h 2c", Nothing, Nothing, +1},
+		{Lowball, "", "7h 5h 4h 3h 2c", "7s 5d 4d 3c 2s", Nothing, Nothing, 0},
+		{Lowball, "", "Kh Qh Jh Th 8c", "As 5h 4h 3h 2c", Nothing, Nothing, -1},
+		{Lowball, "", "As 5h 4h 3h 2c", "As 6h 4h 3h 2c", Nothing, Nothing, -1},
+		{Lowball, "", "As 6h 4h 3h 2c", "2s 2h 4h 3h 5c", Nothing, Pair, -1},
+		{Lowball, "", "8s 6h 5h 4h 3c", "7h 6h 5h 4h 3c", Nothing, Straight, -1},
+		{Lowball, "", "7h 6h 5h 4h 3c", "7h 5h 4h 3h 2h", Straight, Flush, -1},
+		{LowballTriple, "", "Ah 5h 4h 3h 2h", "Kh Qh Jh Th 8h", Flush, Flush, +1},
+		{LowballTriple, "", "Ah 5h 4h 3h 2h", "Ah 6h 4h 3h 2h", Flush, Flush, -1},
+		{LowballTriple, "", "6h 5h 4h 3h 2h", "Ah Ac As Ad 2h", StraightFlush, FourOfAKind, +1},
 	}
 	for i, test := range tests {
 		board := Must(test.board)
@@ -174,6 +614,53 @@ func TestTypeHiComp(t *testing.T) {
 	}
 }
 
+func TestLowballWin(t *testing.T) {
+	tests := []struct {
+		pockets []string
+		exp     []int
+		pivot   int
+		desc    []string
+	}{
+		{
+			[]string{"Ah 5h 4c 3c 2d", "Kh Qh Jh Th 8c", "8c 8d 4c 3c 2d", "7s 6s 5s 4s 2s"},
+			[]int{1, 0, 2, 3},
+			1,
+			[]string{
+				"Ace, Five, Four, Three, Two-low",
+				"King, Queen, Jack, Ten, Eight-low",
+				"Pair, Eights, kickers Four, Three, Two",
+				"Flush, Seven-high",
+			},
+		},
+		{
+			[]string{"7h 5h 4c 3c 2d", "7s 5c 4h 3d 2c", "6s 5s 4s 3s 2h"},
+			[]int{0, 1, 2},
+			2,
+			[]string{
+				"Seven, Five, Four, Three, Two-low, Wheel",
+				"Seven, Five, Four, Three, Two-low, Wheel",
+				"Straight, Six-high",
+			},
+		},
+	}
+	for i, test := range tests {
+		pockets := make([][]Card, len(test.pockets))
+		for j, s := range test.pockets {
+			pockets[j] = Must(s)
+		}
+		hands := Lowball.RankHands(pockets, nil)
+		for j, h := range hands {
+			if s := h.Description(); s != test.desc[j] {
+				t.Errorf("test %d hand %d expected %q, got: %q", i, j, test.desc[j], s)
+			}
+		}
+		win := NewWin(hands, nil, false)
+		if !reflect.DeepEqual(win.Hi, test.exp) || win.HiPivot != test.pivot {
+			t.Errorf("test %d expected %v %d, got: %v %d", i, test.exp, test.pivot, win.Hi, win.HiPivot)
+		}
+	}
+}
+
 func TestNumberedStreets(t *testing.T) {
 	exp := []string{"Ante", "1st", "2nd", "3rd", "4th", "5th", "6th", "7th", "8th", "9th", "10th", "11th", "101st", "102nd", "River"}
 	streets := NumberedStreets(0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 90, 1, 1)
@@ -197,6 +684,8 @@ func TestTypeUnmarshal(t *testing.T) {
 		{"razz", Razz},
 		{"BaDUGI", Badugi},
 		{"fusIon", Fusion},
+		{"Hh", Holdem},
+		{"O5", OmahaFive},
 	}
 	for i, test := range tests {
 		typ := Type(^uint16(0))
@@ -208,3 +697,18 @@ func TestTypeUnmarshal(t *testing.T) {
 		}
 	}
 }
+
+func TestTypeString(t *testing.T) {
+	for _, desc := range DefaultTypes() {
+		typ, err := IdToType(desc.Type.String())
+		if err != nil {
+			t.Fatalf("%s expected no error, got: %v", desc.Name, err)
+		}
+		if typ != desc.Type {
+			t.Errorf("%s expected %d, got: %d", desc.Name, desc.Type, typ)
+		}
+	}
+	if s, exp := Holdem.String(), "Hh"; s != exp {
+		t.Errorf("expected %q, got: %q", exp, s)
+	}
+}